@@ -5,8 +5,30 @@ import (
 
 	"github.com/msales/pkg/v4/cache"
 	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/codec"
 )
 
+// SinkOptFunc represents a function that sets up the Sink.
+type SinkOptFunc func(s *Sink)
+
+// WithKeyEncoder sets the encoder used to create the cache key.
+//
+// By default the key is expected to be a string.
+func WithKeyEncoder(enc codec.Encoder) SinkOptFunc {
+	return func(s *Sink) {
+		s.keyEncoder = enc
+	}
+}
+
+// WithValueEncoder sets the encoder used to encode the cached value.
+//
+// By default the value is cached as is.
+func WithValueEncoder(enc codec.Encoder) SinkOptFunc {
+	return func(s *Sink) {
+		s.valueEncoder = enc
+	}
+}
+
 // Sink represents a Cache streams sink.
 type Sink struct {
 	pipe streams.Pipe
@@ -14,17 +36,27 @@ type Sink struct {
 	cache  cache.Cache
 	expire time.Duration
 
+	keyEncoder   codec.Encoder
+	valueEncoder codec.Encoder
+
 	batch int
 	count int
 }
 
 // NewSink creates a new cache insert sink.
-func NewSink(cache cache.Cache, expire time.Duration, batch int) *Sink {
-	return &Sink{
-		cache:  cache,
-		expire: expire,
-		batch:  batch,
+func NewSink(cache cache.Cache, expire time.Duration, batch int, opts ...SinkOptFunc) *Sink {
+	s := &Sink{
+		cache:      cache,
+		expire:     expire,
+		keyEncoder: codec.StringEncoder{},
+		batch:      batch,
 	}
+
+	for _, optFn := range opts {
+		optFn(s)
+	}
+
+	return s
 }
 
 // WithPipe sets the pipe on the Processor.
@@ -34,9 +66,19 @@ func (p *Sink) WithPipe(pipe streams.Pipe) {
 
 // Process processes the stream record.
 func (p *Sink) Process(msg streams.Message) error {
-	str := msg.Key.(string)
+	k, err := p.keyEncoder.Encode(msg.Key)
+	if err != nil {
+		return err
+	}
+
+	var v interface{} = msg.Value
+	if p.valueEncoder != nil {
+		if v, err = p.valueEncoder.Encode(msg.Value); err != nil {
+			return err
+		}
+	}
 
-	if err := p.cache.Set(str, msg.Value, p.expire); err != nil {
+	if err := p.cache.Set(string(k), v, p.expire); err != nil {
 		return err
 	}
 
@@ -2,12 +2,14 @@ package cache_test
 
 import (
 	"errors"
+	"strconv"
 	"testing"
 	"time"
 
 	cache2 "github.com/msales/pkg/v4/cache"
 	"github.com/rafalmnich/streams/v6"
 	"github.com/rafalmnich/streams/v6/cache"
+	"github.com/rafalmnich/streams/v6/codec"
 	"github.com/rafalmnich/streams/v6/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -61,6 +63,39 @@ func TestSink_ProcessWithCacheError(t *testing.T) {
 	c.AssertExpectations(t)
 }
 
+func TestSink_ProcessWithEncoders(t *testing.T) {
+	c := new(MockCache)
+	c.On("Set", "1", []byte("test"), time.Millisecond).Return(nil)
+	pipe := mocks.NewPipe(t)
+	pipe.ExpectMark(1, "test")
+	keyEnc := codec.EncoderFunc(func(v interface{}) ([]byte, error) {
+		return []byte(strconv.Itoa(v.(int))), nil
+	})
+	s := cache.NewSink(c, time.Millisecond, 10, cache.WithKeyEncoder(keyEnc), cache.WithValueEncoder(codec.StringEncoder{}))
+	s.WithPipe(pipe)
+
+	err := s.Process(streams.NewMessage(1, "test"))
+
+	assert.NoError(t, err)
+	c.AssertExpectations(t)
+	pipe.AssertExpectations()
+}
+
+func TestSink_ProcessWithEncoderError(t *testing.T) {
+	c := new(MockCache)
+	pipe := mocks.NewPipe(t)
+	enc := codec.EncoderFunc(func(interface{}) ([]byte, error) {
+		return nil, errors.New("test error")
+	})
+	s := cache.NewSink(c, time.Millisecond, 1, cache.WithValueEncoder(enc))
+	s.WithPipe(pipe)
+
+	err := s.Process(streams.NewMessage("test", "test"))
+
+	assert.Error(t, err)
+	c.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestSink_Close(t *testing.T) {
 	pipe := mocks.NewPipe(t)
 	s := cache.NewSink(cache2.Null, time.Millisecond, 1)
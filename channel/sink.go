@@ -1,6 +1,26 @@
 package channel
 
-import "github.com/rafalmnich/streams/v6"
+import (
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/codec"
+)
+
+// SinkOptFunc represents a function that sets up the Sink.
+type SinkOptFunc func(s *Sink)
+
+// WithKeyEncoder sets the encoder used on the Message key before it is sent.
+func WithKeyEncoder(enc codec.Encoder) SinkOptFunc {
+	return func(s *Sink) {
+		s.keyEncoder = enc
+	}
+}
+
+// WithValueEncoder sets the encoder used on the Message value before it is sent.
+func WithValueEncoder(enc codec.Encoder) SinkOptFunc {
+	return func(s *Sink) {
+		s.valueEncoder = enc
+	}
+}
 
 // Sink represents a channel sink.
 type Sink struct {
@@ -8,6 +28,9 @@ type Sink struct {
 
 	ch chan streams.Message
 
+	keyEncoder   codec.Encoder
+	valueEncoder codec.Encoder
+
 	batch int
 	count int
 }
@@ -15,11 +38,17 @@ type Sink struct {
 // NewSink creates a new channel Sink.
 //
 // A batch size of 0 will never commit.
-func NewSink(ch chan streams.Message, batch int) *Sink {
-	return &Sink{
+func NewSink(ch chan streams.Message, batch int, opts ...SinkOptFunc) *Sink {
+	s := &Sink{
 		ch:    ch,
 		batch: batch,
 	}
+
+	for _, optFn := range opts {
+		optFn(s)
+	}
+
+	return s
 }
 
 // WithPipe sets the pipe on the Processor.
@@ -29,7 +58,12 @@ func (s *Sink) WithPipe(pipe streams.Pipe) {
 
 // Process processes the stream Message.
 func (s *Sink) Process(msg streams.Message) error {
-	s.ch <- msg
+	out, err := codec.EncodeMessage(msg, s.keyEncoder, s.valueEncoder)
+	if err != nil {
+		return err
+	}
+
+	s.ch <- out
 
 	s.count++
 	if s.batch > 0 && s.count >= s.batch {
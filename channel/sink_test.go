@@ -1,10 +1,12 @@
 package channel_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/rafalmnich/streams/v6"
 	"github.com/rafalmnich/streams/v6/channel"
+	"github.com/rafalmnich/streams/v6/codec"
 	"github.com/rafalmnich/streams/v6/mocks"
 	"github.com/stretchr/testify/assert"
 )
@@ -51,6 +53,38 @@ func TestSink_ProcessWithCommit(t *testing.T) {
 	pipe.AssertExpectations()
 }
 
+func TestSink_ProcessWithEncoders(t *testing.T) {
+	ch := make(chan streams.Message, 1)
+	sink := channel.NewSink(ch, 2, channel.WithKeyEncoder(codec.StringEncoder{}), channel.WithValueEncoder(codec.StringEncoder{}))
+
+	pipe := mocks.NewPipe(t)
+	pipe.ExpectMark("foo", "test")
+
+	sink.WithPipe(pipe)
+
+	err := sink.Process(streams.NewMessage("foo", "test"))
+
+	assert.NoError(t, err)
+	got := <-ch
+	assert.Equal(t, []byte("foo"), got.Key)
+	assert.Equal(t, []byte("test"), got.Value)
+	pipe.AssertExpectations()
+}
+
+func TestSink_ProcessWithEncoderError(t *testing.T) {
+	ch := make(chan streams.Message, 1)
+	enc := codec.EncoderFunc(func(interface{}) ([]byte, error) {
+		return nil, errors.New("test")
+	})
+	sink := channel.NewSink(ch, 1, channel.WithValueEncoder(enc))
+	sink.WithPipe(mocks.NewPipe(t))
+
+	err := sink.Process(streams.NewMessage(nil, "test"))
+
+	assert.Error(t, err)
+	assert.Len(t, ch, 0)
+}
+
 func TestSink_Close(t *testing.T) {
 	ch := make(chan streams.Message)
 	sink := channel.NewSink(ch, 1)
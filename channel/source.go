@@ -1,36 +1,98 @@
 package channel
 
 import (
+	"context"
+	"io"
 	"time"
 
 	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/codec"
 )
 
 // Compile-time interface check.
-var _ streams.Source = (*Source)(nil)
+var _ streams.ContextSource = (*Source)(nil)
+
+// SourceOptFunc represents a function that sets up the Source.
+type SourceOptFunc func(s *Source)
+
+// WithKeyDecoder sets the decoder used on the Message key when it is consumed.
+func WithKeyDecoder(dec codec.Decoder) SourceOptFunc {
+	return func(s *Source) {
+		s.keyDecoder = dec
+	}
+}
+
+// WithValueDecoder sets the decoder used on the Message value when it is consumed.
+func WithValueDecoder(dec codec.Decoder) SourceOptFunc {
+	return func(s *Source) {
+		s.valueDecoder = dec
+	}
+}
 
 // Source represents a source that consumes messages from a channel.
+//
+// Once the channel is closed, the Source returns io.EOF.
 type Source struct {
 	ch chan streams.Message
+
+	keyDecoder   codec.Decoder
+	valueDecoder codec.Decoder
 }
 
 // NewSource creates a new channel Source.
-func NewSource(ch chan streams.Message) *Source {
-	return &Source{ch: ch}
+func NewSource(ch chan streams.Message, opts ...SourceOptFunc) *Source {
+	s := &Source{ch: ch}
+
+	for _, optFn := range opts {
+		optFn(s)
+	}
+
+	return s
 }
 
 // Consume gets the next record from the Source.
 func (s *Source) Consume() (streams.Message, error) {
 	select {
 
-	case msg := <-s.ch:
-		return msg.WithMetadata(nil, nil), nil
+	case msg, ok := <-s.ch:
+		if !ok {
+			return streams.EmptyMessage, io.EOF
+		}
+
+		return s.message(msg)
 
 	case <-time.After(100 * time.Millisecond):
 		return streams.NewMessage(nil, nil), nil
 	}
 }
 
+// ConsumeContext gets the next record from the Source, blocking until
+// a record is available or the context is done.
+func (s *Source) ConsumeContext(ctx context.Context) (streams.Message, error) {
+	select {
+
+	case msg, ok := <-s.ch:
+		if !ok {
+			return streams.EmptyMessage, io.EOF
+		}
+
+		return s.message(msg)
+
+	case <-ctx.Done():
+		return streams.EmptyMessage, ctx.Err()
+	}
+}
+
+// message decodes the channel Message.
+func (s *Source) message(msg streams.Message) (streams.Message, error) {
+	msg, err := codec.DecodeMessage(msg, s.keyDecoder, s.valueDecoder)
+	if err != nil {
+		return streams.EmptyMessage, err
+	}
+
+	return msg.WithMetadata(nil, nil), nil
+}
+
 // Commit marks the consumed records as processed.
 func (s *Source) Commit(interface{}) error {
 	return nil
@@ -1,10 +1,13 @@
 package channel_test
 
 import (
+	"context"
+	"io"
 	"testing"
 
 	"github.com/rafalmnich/streams/v6"
 	"github.com/rafalmnich/streams/v6/channel"
+	"github.com/rafalmnich/streams/v6/codec"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -40,6 +43,28 @@ func TestSource_Consume(t *testing.T) {
 	}
 }
 
+func TestSource_ConsumeWithDecoders(t *testing.T) {
+	ch := make(chan streams.Message, 1)
+	ch <- streams.NewMessage([]byte("foo"), []byte("bar"))
+	src := channel.NewSource(ch, channel.WithKeyDecoder(codec.StringDecoder{}), channel.WithValueDecoder(codec.StringDecoder{}))
+
+	msg, err := src.Consume()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", msg.Key)
+	assert.Equal(t, "bar", msg.Value)
+}
+
+func TestSource_ConsumeWithDecoderError(t *testing.T) {
+	ch := make(chan streams.Message, 1)
+	ch <- streams.NewMessage(nil, 1)
+	src := channel.NewSource(ch, channel.WithValueDecoder(codec.StringDecoder{}))
+
+	_, err := src.Consume()
+
+	assert.Error(t, err)
+}
+
 func TestSource_Consume_WithEmptyMessage(t *testing.T) {
 	src := channel.NewSource(nil)
 
@@ -48,6 +73,62 @@ func TestSource_Consume_WithEmptyMessage(t *testing.T) {
 	assert.True(t, msg.Empty())
 }
 
+func TestSource_ConsumeContext(t *testing.T) {
+	ch := make(chan streams.Message, 1)
+	ch <- streams.NewMessage("foo", "bar")
+	src := channel.NewSource(ch)
+
+	msg, err := src.ConsumeContext(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", msg.Key)
+	assert.Equal(t, "bar", msg.Value)
+}
+
+func TestSource_ConsumeContextCancelled(t *testing.T) {
+	tests := []struct {
+		name string
+		ch   chan streams.Message
+	}{
+		{name: "Open Channel", ch: make(chan streams.Message)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := channel.NewSource(tt.ch)
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			msg, err := src.ConsumeContext(ctx)
+
+			assert.Equal(t, context.Canceled, err)
+			assert.True(t, msg.Empty())
+		})
+	}
+}
+
+func TestSource_ConsumeClosedChannel(t *testing.T) {
+	ch := make(chan streams.Message)
+	close(ch)
+	src := channel.NewSource(ch)
+
+	msg, err := src.Consume()
+
+	assert.Equal(t, io.EOF, err)
+	assert.True(t, msg.Empty())
+}
+
+func TestSource_ConsumeContextClosedChannel(t *testing.T) {
+	ch := make(chan streams.Message)
+	close(ch)
+	src := channel.NewSource(ch)
+
+	msg, err := src.ConsumeContext(context.Background())
+
+	assert.Equal(t, io.EOF, err)
+	assert.True(t, msg.Empty())
+}
+
 func TestSource_Commit(t *testing.T) {
 	src := channel.NewSource(nil)
 
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/Shopify/sarama"
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/channel"
+	"github.com/rafalmnich/streams/v6/codec"
+	"github.com/rafalmnich/streams/v6/file"
+	"github.com/rafalmnich/streams/v6/kafka"
+	"github.com/rafalmnich/streams/v6/pipeline"
+	sqlsink "github.com/rafalmnich/streams/v6/sql"
+	"golang.org/x/xerrors"
+)
+
+const defaultBatchSize = 1000
+
+// env represents the environment the connectors run in.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+
+	// wg tracks the goroutines of the channel connectors.
+	wg sync.WaitGroup
+}
+
+// newRegistry creates a registry of the built-in connectors.
+//
+// When dry is set, the parameters are validated but nothing is opened,
+// and stub sources and processors are returned.
+func newRegistry(e *env, dry bool) *pipeline.Registry {
+	r := pipeline.NewRegistry()
+
+	r.RegisterSource("kafka", func(p pipeline.Params) (streams.Source, error) {
+		var params kafkaSourceParams
+		if err := decodeParams(p, &params); err != nil || dry {
+			return &stubSource{params: p}, err
+		}
+
+		return params.open()
+	})
+	r.RegisterSource("file", func(p pipeline.Params) (streams.Source, error) {
+		var params fileParams
+		if err := decodeParams(p, &params); err != nil || dry {
+			return &stubSource{params: p}, err
+		}
+
+		return file.NewSource(params.Path)
+	})
+	r.RegisterSource("stdin", func(p pipeline.Params) (streams.Source, error) {
+		if dry {
+			return &stubSource{params: p}, nil
+		}
+
+		return e.stdinSource(), nil
+	})
+
+	r.RegisterProcessor("kafka", func(p pipeline.Params) (streams.Processor, error) {
+		var params kafkaSinkParams
+		if err := decodeParams(p, &params); err != nil || dry {
+			return &stubCommitter{}, err
+		}
+
+		return params.open()
+	})
+	r.RegisterProcessor("file", func(p pipeline.Params) (streams.Processor, error) {
+		var params fileParams
+		if err := decodeParams(p, &params); err != nil || dry {
+			return &stubCommitter{}, err
+		}
+
+		return file.NewSink(params.Path, params.BatchSize)
+	})
+	r.RegisterProcessor("sql", func(p pipeline.Params) (streams.Processor, error) {
+		var params sqlParams
+		if err := decodeParams(p, &params); err != nil || dry {
+			return &stubCommitter{}, err
+		}
+
+		return params.open()
+	})
+	r.RegisterProcessor("stdout", func(p pipeline.Params) (streams.Processor, error) {
+		if dry {
+			return &stubProcessor{}, nil
+		}
+
+		return e.stdoutSink(), nil
+	})
+	r.RegisterProcessor("print", func(p pipeline.Params) (streams.Processor, error) {
+		if dry {
+			return &stubProcessor{}, nil
+		}
+
+		return streams.NewPrintProcessor(), nil
+	})
+
+	return r
+}
+
+// params represents the parameters of a connector.
+type params interface {
+	validate() error
+}
+
+// decodeParams decodes and validates the connector parameters.
+func decodeParams(p pipeline.Params, v interface{}) error {
+	if err := p.Decode(v); err != nil {
+		return err
+	}
+
+	if params, ok := v.(params); ok {
+		return params.validate()
+	}
+
+	return nil
+}
+
+// newCodec returns the decoder and encoder of the named codec.
+func newCodec(name string) (codec.Decoder, codec.Encoder, error) {
+	switch name {
+	case "", "bytes":
+		return codec.ByteDecoder{}, codec.ByteEncoder{}, nil
+	case "string":
+		return codec.StringDecoder{}, codec.StringEncoder{}, nil
+	default:
+		return nil, nil, xerrors.Errorf("unknown codec %q", name)
+	}
+}
+
+type kafkaParams struct {
+	Brokers    []string `json:"brokers"`
+	Topic      string   `json:"topic"`
+	Version    string   `json:"version"`
+	KeyCodec   string   `json:"keyCodec"`
+	ValueCodec string   `json:"valueCodec"`
+}
+
+func (p kafkaParams) validate() error {
+	if len(p.Brokers) == 0 {
+		return errors.New("brokers are required")
+	}
+	if p.Topic == "" {
+		return errors.New("topic is required")
+	}
+	if p.Version != "" {
+		if _, err := sarama.ParseKafkaVersion(p.Version); err != nil {
+			return err
+		}
+	}
+	if _, _, err := newCodec(p.KeyCodec); err != nil {
+		return err
+	}
+	_, _, err := newCodec(p.ValueCodec)
+
+	return err
+}
+
+// config applies the parameters to the sarama configuration.
+func (p kafkaParams) config(c *sarama.Config) {
+	if p.Version != "" {
+		c.Version, _ = sarama.ParseKafkaVersion(p.Version)
+	}
+}
+
+type kafkaSourceParams struct {
+	kafkaParams
+
+	GroupID string `json:"groupId"`
+}
+
+func (p kafkaSourceParams) validate() error {
+	if p.GroupID == "" {
+		return errors.New("groupId is required")
+	}
+
+	return p.kafkaParams.validate()
+}
+
+func (p kafkaSourceParams) open() (streams.Source, error) {
+	c := kafka.NewSourceConfig()
+	p.config(&c.Config)
+	c.Brokers = p.Brokers
+	c.Topic = p.Topic
+	c.GroupID = p.GroupID
+	c.KeyDecoder, _, _ = newCodec(p.KeyCodec)
+	c.ValueDecoder, _, _ = newCodec(p.ValueCodec)
+
+	return kafka.NewSource(c)
+}
+
+type kafkaSinkParams struct {
+	kafkaParams
+
+	BatchSize int `json:"batchSize"`
+}
+
+func (p kafkaSinkParams) open() (streams.Processor, error) {
+	c := kafka.NewSinkConfig()
+	p.config(&c.Config)
+	c.Brokers = p.Brokers
+	c.Topic = p.Topic
+	_, c.KeyEncoder, _ = newCodec(p.KeyCodec)
+	_, c.ValueEncoder, _ = newCodec(p.ValueCodec)
+	if p.BatchSize > 0 {
+		c.BatchSize = p.BatchSize
+	}
+
+	return kafka.NewSink(c)
+}
+
+type fileParams struct {
+	Path      string `json:"path"`
+	BatchSize int    `json:"batchSize"`
+}
+
+func (p *fileParams) validate() error {
+	if p.Path == "" {
+		return errors.New("path is required")
+	}
+	if p.BatchSize <= 0 {
+		p.BatchSize = defaultBatchSize
+	}
+
+	return nil
+}
+
+type sqlParams struct {
+	Driver    string `json:"driver"`
+	DSN       string `json:"dsn"`
+	Query     string `json:"query"`
+	BatchSize int    `json:"batchSize"`
+}
+
+func (p *sqlParams) validate() error {
+	switch {
+	case p.Driver == "":
+		return errors.New("driver is required")
+	case p.DSN == "":
+		return errors.New("dsn is required")
+	case p.Query == "":
+		return errors.New("query is required")
+	}
+
+	if p.BatchSize <= 0 {
+		p.BatchSize = defaultBatchSize
+	}
+
+	return nil
+}
+
+// open opens the database and creates a sink executing the query
+// with the key and value of every message as arguments.
+//
+// The database driver must be linked into the binary.
+func (p *sqlParams) open() (streams.Processor, error) {
+	db, err := sql.Open(p.Driver, p.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	exec := sqlsink.ExecFunc(func(tx *sql.Tx, msg streams.Message) error {
+		_, err := tx.Exec(p.Query, msg.Key, msg.Value)
+		return err
+	})
+
+	return sqlsink.NewSink(db, p.BatchSize, exec)
+}
+
+// stdinSource creates a channel source consuming the lines of stdin.
+func (e *env) stdinSource() streams.Source {
+	ch := make(chan streams.Message)
+
+	go func() {
+		defer close(ch)
+
+		r := bufio.NewReader(e.stdin)
+		for {
+			line, err := r.ReadBytes('\n')
+			if len(line) > 0 {
+				ch <- streams.NewMessage(nil, bytes.TrimRight(line, "\r\n"))
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return channel.NewSource(ch)
+}
+
+// stdoutSink creates a channel sink writing the message values to stdout.
+func (e *env) stdoutSink() streams.Processor {
+	ch := make(chan streams.Message, defaultBatchSize)
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+
+		for msg := range ch {
+			_, _ = e.stdout.Write(append(msg.Value.([]byte), '\n'))
+		}
+	}()
+
+	return channel.NewSink(ch, 0, channel.WithValueEncoder(lineEncoder{}))
+}
+
+// lineEncoder encodes byte and string values, and formats other values.
+type lineEncoder struct{}
+
+// Encode encodes the value.
+func (lineEncoder) Encode(v interface{}) ([]byte, error) {
+	switch b := v.(type) {
+	case []byte:
+		return b, nil
+	case string:
+		return []byte(b), nil
+	default:
+		return []byte(fmt.Sprint(v)), nil
+	}
+}
+
+// stubSource is a source standing in for a connector that is not opened.
+type stubSource struct {
+	params pipeline.Params
+}
+
+func (*stubSource) Consume() (streams.Message, error) {
+	return streams.EmptyMessage, io.EOF
+}
+
+func (*stubSource) Commit(interface{}) error {
+	return nil
+}
+
+func (*stubSource) Close() error {
+	return nil
+}
+
+// stubProcessor is a processor standing in for a connector that is not opened.
+type stubProcessor struct {
+	pipe streams.Pipe
+}
+
+func (p *stubProcessor) WithPipe(pipe streams.Pipe) {
+	p.pipe = pipe
+}
+
+func (p *stubProcessor) Process(msg streams.Message) error {
+	return p.pipe.Forward(msg)
+}
+
+func (p *stubProcessor) Close() error {
+	return nil
+}
+
+// stubCommitter is a committer standing in for a connector that is not opened.
+type stubCommitter struct {
+	stubProcessor
+}
+
+func (p *stubCommitter) Commit(ctx context.Context) error {
+	return nil
+}
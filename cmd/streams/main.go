@@ -0,0 +1,278 @@
+// Command streams validates, describes, runs and replays declarative pipelines.
+//
+// Usage:
+//
+//	streams validate -f pipeline.yaml
+//	streams describe -f pipeline.yaml [-format dot|mermaid|json]
+//	streams run -f pipeline.yaml [-mode async|sync] [-commit-interval 1s]
+//	streams replay -f pipeline.yaml -input messages.jsonl [-mode async|sync]
+//
+// The built-in connectors are:
+//
+//	kafka   source and sink   brokers, topic, groupId (source), version, keyCodec, valueCodec, batchSize (sink)
+//	file    source and sink   path, batchSize (sink)
+//	sql     sink              driver, dsn, query, batchSize
+//	stdin   source            channel source consuming the lines of stdin
+//	stdout  sink              channel sink writing the message values to stdout
+//	print   processor         prints the messages
+//
+// The codecs are "bytes" (default) and "string". The sql driver must be linked into the binary.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/pipeline"
+)
+
+const usage = `usage: streams <command> [flags]
+
+commands:
+  validate  validate a pipeline file
+  describe  describe the topology of a pipeline file
+  run       run a pipeline file until interrupted or its sources are exhausted
+  replay    replay a file of recorded messages through a pipeline file
+`
+
+func main() {
+	e := &env{stdin: os.Stdin, stdout: os.Stdout}
+
+	os.Exit(run(context.Background(), e, os.Args[1:], os.Stderr))
+}
+
+// run runs the command in the arguments, returning the exit code.
+func run(ctx context.Context, e *env, args []string, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	cmds := map[string]func(context.Context, *env, []string, io.Writer) error{
+		"validate": validateCmd,
+		"describe": describeCmd,
+		"run":      runCmd,
+		"replay":   replayCmd,
+	}
+
+	cmd, ok := cmds[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "streams: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	if err := cmd(ctx, e, args[1:], stderr); err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintln(stderr, err)
+		}
+		return 1
+	}
+
+	return 0
+}
+
+// errInvalid is returned when the pipeline is invalid. The errors have already been reported.
+var errInvalid = errors.New("streams: invalid pipeline")
+
+func newFlagSet(name string, stderr io.Writer) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("f", "", "the pipeline `file`")
+
+	return fs, path
+}
+
+// parseDefinition parses the pipeline file at the path.
+func parseDefinition(path string) (*pipeline.Definition, error) {
+	if path == "" {
+		return nil, errors.New("streams: a pipeline file is required")
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return pipeline.Parse(b)
+}
+
+// build builds the topology of the definition, reporting the errors.
+func build(reg *pipeline.Registry, def *pipeline.Definition, stderr io.Writer) (*streams.Topology, error) {
+	tp, errs := reg.Build(def)
+	for _, err := range errs {
+		fmt.Fprintln(stderr, err)
+	}
+
+	if len(errs) > 0 {
+		return tp, errInvalid
+	}
+
+	return tp, nil
+}
+
+func validateCmd(_ context.Context, e *env, args []string, stderr io.Writer) error {
+	fs, path := newFlagSet("validate", stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	def, err := parseDefinition(*path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := build(newRegistry(e, true), def, stderr); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(e.stdout, "pipeline is valid")
+
+	return nil
+}
+
+func describeCmd(_ context.Context, e *env, args []string, stderr io.Writer) error {
+	fs, path := newFlagSet("describe", stderr)
+	format := fs.String("format", "dot", "the output `format`: dot, mermaid or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	def, err := parseDefinition(*path)
+	if err != nil {
+		return err
+	}
+
+	tp, err := build(newRegistry(e, true), def, stderr)
+	if tp == nil {
+		return err
+	}
+
+	desc := describe(tp, def)
+
+	switch *format {
+	case "dot":
+		fmt.Fprint(e.stdout, desc.DOT())
+	case "mermaid":
+		fmt.Fprint(e.stdout, desc.Mermaid())
+	case "json":
+		b, jsonErr := desc.JSON()
+		if jsonErr != nil {
+			return jsonErr
+		}
+		fmt.Fprintln(e.stdout, string(b))
+	default:
+		return fmt.Errorf("streams: unknown format %q", *format)
+	}
+
+	return err
+}
+
+// describe describes the topology, using the connector types of the definition.
+func describe(tp *streams.Topology, def *pipeline.Definition) streams.TopologyDescription {
+	types := map[string]string{}
+	for _, nodes := range [][]pipeline.NodeDefinition{def.Sources, def.Processors, def.Sinks} {
+		for _, n := range nodes {
+			types[n.Name] = n.Type
+		}
+	}
+
+	desc := tp.Describe()
+	for _, nodes := range [][]streams.NodeDescription{desc.Sources, desc.Processors} {
+		for i := range nodes {
+			if typ, ok := types[nodes[i].Name]; ok {
+				nodes[i].Type = typ
+			}
+		}
+	}
+
+	return desc
+}
+
+// taskFlags registers the task flags, returning a function creating the task options.
+func taskFlags(fs *flag.FlagSet) func() ([]streams.TaskOptFunc, error) {
+	mode := fs.String("mode", "async", "the task `mode`: async or sync")
+	interval := fs.Duration("commit-interval", 0, "the `interval` of automatic commits, disabled if 0")
+
+	return func() ([]streams.TaskOptFunc, error) {
+		var opts []streams.TaskOptFunc
+
+		switch *mode {
+		case "async":
+			opts = append(opts, streams.WithMode(streams.Async))
+		case "sync":
+			opts = append(opts, streams.WithMode(streams.Sync))
+		default:
+			return nil, fmt.Errorf("streams: unknown mode %q", *mode)
+		}
+
+		if *interval > 0 {
+			opts = append(opts, streams.WithCommitInterval(*interval))
+		}
+
+		return opts, nil
+	}
+}
+
+func runCmd(ctx context.Context, e *env, args []string, stderr io.Writer) error {
+	fs, path := newFlagSet("run", stderr)
+	opts := taskFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	taskOpts, err := opts()
+	if err != nil {
+		return err
+	}
+
+	def, err := parseDefinition(*path)
+	if err != nil {
+		return err
+	}
+
+	tp, err := build(newRegistry(e, false), def, stderr)
+	if err != nil {
+		return err
+	}
+
+	return runTopology(ctx, e, tp, taskOpts)
+}
+
+// runTopology runs the topology until the context is done, a SIGINT or
+// SIGTERM is received, or the task finishes.
+func runTopology(ctx context.Context, e *env, tp *streams.Topology, opts []streams.TaskOptFunc) error {
+	task := streams.NewTask(tp, opts...)
+
+	// The task is started with the parent context, not the signal context,
+	// so a signal does not cancel the final commit of the shutdown.
+	if err := task.Start(ctx); err != nil {
+		return err
+	}
+
+	sigCtx, cancel := streams.SignalContext(ctx)
+	defer cancel()
+
+	select {
+	case <-sigCtx.Done():
+	case <-task.Done():
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	err := task.Shutdown(shutdownCtx)
+	if waitErr := task.Wait(); waitErr != nil {
+		err = waitErr
+	}
+
+	e.wg.Wait()
+
+	return err
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func runTest(args ...string) (int, string, string) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	e := &env{stdin: strings.NewReader("first\nsecond\n"), stdout: stdout}
+
+	code := run(context.Background(), e, args, stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_Usage(t *testing.T) {
+	code, _, stderr := runTest()
+
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "usage: streams")
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	code, _, stderr := runTest("nope")
+
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `unknown command "nope"`)
+}
+
+func TestRun_Validate(t *testing.T) {
+	code, stdout, _ := runTest("validate", "-f", "testdata/pipeline.yaml")
+
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "pipeline is valid\n", stdout)
+}
+
+func TestRun_ValidateInvalid(t *testing.T) {
+	code, _, stderr := runTest("validate", "-f", "testdata/invalid.yaml")
+
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "pipeline: sources[1].params: brokers are required")
+	assert.Contains(t, stderr, `pipeline: sinks[0].type: unknown processor type "nope"`)
+}
+
+func TestRun_ValidateMissingFile(t *testing.T) {
+	code, _, stderr := runTest("validate")
+
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "a pipeline file is required")
+}
+
+func TestRun_Describe(t *testing.T) {
+	code, stdout, _ := runTest("describe", "-f", "testdata/pipeline.yaml", "-format", "mermaid")
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "in<br/>stdin")
+	assert.Contains(t, stdout, "out<br/>stdout")
+}
+
+func TestRun_DescribeUnknownFormat(t *testing.T) {
+	code, _, stderr := runTest("describe", "-f", "testdata/pipeline.yaml", "-format", "nope")
+
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, `unknown format "nope"`)
+}
+
+func TestRun_Run(t *testing.T) {
+	code, stdout, stderr := runTest("run", "-f", "testdata/pipeline.yaml", "-mode", "sync")
+
+	assert.Equal(t, 0, code, stderr)
+	assert.Equal(t, "first\nsecond\n", stdout)
+}
+
+func TestRun_Replay(t *testing.T) {
+	code, stdout, stderr := runTest("replay", "-f", "testdata/pipeline.yaml", "-input", "testdata/records.jsonl")
+
+	assert.Equal(t, 0, code, stderr)
+	assert.Equal(t, "first\nsecond\n", stdout)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/channel"
+	"github.com/rafalmnich/streams/v6/pipeline"
+)
+
+// record represents a recorded message.
+type record struct {
+	Source string  `json:"source"`
+	Key    *string `json:"key"`
+	Value  string  `json:"value"`
+}
+
+func replayCmd(ctx context.Context, e *env, args []string, stderr io.Writer) error {
+	fs, path := newFlagSet("replay", stderr)
+	input := fs.String("input", "", "the `file` of recorded messages, one JSON object per line")
+	opts := taskFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	taskOpts, err := opts()
+	if err != nil {
+		return err
+	}
+
+	def, err := parseDefinition(*path)
+	if err != nil {
+		return err
+	}
+
+	if *input == "" {
+		return errors.New("streams: an input file is required")
+	}
+
+	f, err := os.Open(*input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	msgs, err := readRecords(f, def)
+	if err != nil {
+		return err
+	}
+
+	reg := newRegistry(e, false)
+	reg.RegisterSource("replay", func(p pipeline.Params) (streams.Source, error) {
+		name, _ := p["source"].(string)
+
+		ch := make(chan streams.Message, len(msgs[name]))
+		for _, msg := range msgs[name] {
+			ch <- msg
+		}
+		close(ch)
+
+		return channel.NewSource(ch), nil
+	})
+
+	for i, n := range def.Sources {
+		def.Sources[i].Type = "replay"
+		def.Sources[i].Params = pipeline.Params{"source": n.Name}
+	}
+
+	tp, err := build(reg, def, stderr)
+	if err != nil {
+		return err
+	}
+
+	return runTopology(ctx, e, tp, taskOpts)
+}
+
+// readRecords reads the recorded messages, grouping them by source.
+//
+// Records without a source are replayed through the only source of the definition.
+func readRecords(r io.Reader, def *pipeline.Definition) (map[string][]streams.Message, error) {
+	sources := map[string]bool{}
+	for _, n := range def.Sources {
+		sources[n.Name] = true
+	}
+
+	msgs := map[string][]streams.Message{}
+
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 16*1024*1024)
+	for line := 1; s.Scan(); line++ {
+		if len(s.Bytes()) == 0 {
+			continue
+		}
+
+		var rec record
+		if err := json.Unmarshal(s.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("streams: input line %d: %w", line, err)
+		}
+
+		if rec.Source == "" {
+			if len(def.Sources) != 1 {
+				return nil, fmt.Errorf("streams: input line %d: source is required", line)
+			}
+			rec.Source = def.Sources[0].Name
+		}
+		if !sources[rec.Source] {
+			return nil, fmt.Errorf("streams: input line %d: unknown source %q", line, rec.Source)
+		}
+
+		var key interface{}
+		if rec.Key != nil {
+			key = []byte(*rec.Key)
+		}
+		msgs[rec.Source] = append(msgs[rec.Source], streams.NewMessage(key, []byte(rec.Value)))
+	}
+
+	return msgs, s.Err()
+}
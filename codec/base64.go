@@ -0,0 +1,39 @@
+package codec
+
+import "encoding/base64"
+
+// NewBase64Encoder creates an encoder that base64 encodes the output of the inner encoder.
+//
+// A nil inner encoder expects the data to be bytes.
+func NewBase64Encoder(inner Encoder) Encoder {
+	return EncoderFunc(func(v interface{}) ([]byte, error) {
+		b, err := encode(inner, v)
+		if err != nil || b == nil {
+			return b, err
+		}
+
+		buf := make([]byte, base64.StdEncoding.EncodedLen(len(b)))
+		base64.StdEncoding.Encode(buf, b)
+
+		return buf, nil
+	})
+}
+
+// NewBase64Decoder creates a decoder that base64 decodes the data before passing it to the inner decoder.
+//
+// A nil inner decoder returns the decoded bytes.
+func NewBase64Decoder(inner Decoder) Decoder {
+	return DecoderFunc(func(b []byte) (interface{}, error) {
+		if b == nil {
+			return decode(inner, nil)
+		}
+
+		buf := make([]byte, base64.StdEncoding.DecodedLen(len(b)))
+		n, err := base64.StdEncoding.Decode(buf, b)
+		if err != nil {
+			return nil, err
+		}
+
+		return decode(inner, buf[:n])
+	})
+}
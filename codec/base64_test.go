@@ -0,0 +1,95 @@
+package codec_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rafalmnich/streams/v6/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBase64Encoder_Encode(t *testing.T) {
+	tests := []struct {
+		name string
+		enc  codec.Encoder
+		in   interface{}
+		want []byte
+	}{
+		{
+			name: "Bytes",
+			enc:  codec.NewBase64Encoder(nil),
+			in:   []byte("foobar"),
+			want: []byte("Zm9vYmFy"),
+		},
+		{
+			name: "Inner Encoder",
+			enc:  codec.NewBase64Encoder(codec.StringEncoder{}),
+			in:   "foobar",
+			want: []byte("Zm9vYmFy"),
+		},
+		{
+			name: "Nil",
+			enc:  codec.NewBase64Encoder(nil),
+			in:   nil,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.enc.Encode(tt.in)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBase64Encoder_EncodeError(t *testing.T) {
+	enc := codec.NewBase64Encoder(codec.EncoderFunc(func(interface{}) ([]byte, error) {
+		return nil, errors.New("test")
+	}))
+
+	_, err := enc.Encode("foobar")
+
+	assert.Error(t, err)
+}
+
+func TestBase64Decoder_Decode(t *testing.T) {
+	tests := []struct {
+		name string
+		dec  codec.Decoder
+		in   []byte
+		want interface{}
+	}{
+		{
+			name: "Bytes",
+			dec:  codec.NewBase64Decoder(nil),
+			in:   []byte("Zm9vYmFy"),
+			want: []byte("foobar"),
+		},
+		{
+			name: "Inner Decoder",
+			dec:  codec.NewBase64Decoder(codec.StringDecoder{}),
+			in:   []byte("Zm9vYmFy"),
+			want: "foobar",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.dec.Decode(tt.in)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBase64Decoder_DecodeError(t *testing.T) {
+	dec := codec.NewBase64Decoder(nil)
+
+	_, err := dec.Decode([]byte("!!!"))
+
+	assert.Error(t, err)
+}
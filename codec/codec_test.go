@@ -0,0 +1,51 @@
+package codec_test
+
+import (
+	"testing"
+
+	"github.com/rafalmnich/streams/v6/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByteDecoder_Decode(t *testing.T) {
+	dec := codec.ByteDecoder{}
+
+	got, err := dec.Decode([]byte("foobar"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("foobar"), got)
+}
+
+func TestByteEncoder_Encode(t *testing.T) {
+	enc := codec.ByteEncoder{}
+
+	got, err := enc.Encode([]byte("foobar"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("foobar"), got)
+}
+
+func TestStringDecoder_Decode(t *testing.T) {
+	dec := codec.StringDecoder{}
+
+	got, err := dec.Decode([]byte("foobar"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "foobar", got)
+}
+
+func TestStringEncoder_Encode(t *testing.T) {
+	enc := codec.StringEncoder{}
+
+	got, err := enc.Encode("foobar")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("foobar"), got)
+}
+
+func TestNilDecoder(t *testing.T) {
+	got, err := codec.NilDecoder.Decode([]byte("foobar"))
+
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
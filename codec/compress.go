@@ -0,0 +1,224 @@
+package codec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4"
+	"golang.org/x/xerrors"
+)
+
+// Compression represents a payload compression algorithm.
+type Compression uint8
+
+// Compression types.
+const (
+	Gzip Compression = iota
+	Zstd
+	Snappy
+	LZ4
+)
+
+// compressor represents a compression algorithm and the id prefixed to its payloads.
+type compressor struct {
+	id         byte
+	compress   func([]byte) ([]byte, error)
+	decompress func([]byte) ([]byte, error)
+}
+
+// compressors contains the supported compression algorithms. The ids are part
+// of the encoded payloads, and must never change.
+var compressors = map[Compression]compressor{
+	Gzip: {
+		id: 1,
+		compress: func(b []byte) ([]byte, error) {
+			return compressStream(b, func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) })
+		},
+		decompress: func(b []byte) ([]byte, error) {
+			r, err := gzip.NewReader(bytes.NewReader(b))
+			if err != nil {
+				return nil, err
+			}
+			defer r.Close()
+
+			return ioutil.ReadAll(r)
+		},
+	},
+	Zstd: {
+		id: 2,
+		compress: func(b []byte) ([]byte, error) {
+			enc, err := zstdEncoder()
+			if err != nil {
+				return nil, err
+			}
+
+			return enc.EncodeAll(b, nil), nil
+		},
+		decompress: func(b []byte) ([]byte, error) {
+			dec, err := zstdDecoder()
+			if err != nil {
+				return nil, err
+			}
+
+			return dec.DecodeAll(b, nil)
+		},
+	},
+	Snappy: {
+		id: 3,
+		compress: func(b []byte) ([]byte, error) {
+			return compressStream(b, func(w io.Writer) io.WriteCloser { return snappy.NewBufferedWriter(w) })
+		},
+		decompress: func(b []byte) ([]byte, error) {
+			return ioutil.ReadAll(snappy.NewReader(bytes.NewReader(b)))
+		},
+	},
+	LZ4: {
+		id: 4,
+		compress: func(b []byte) ([]byte, error) {
+			return compressStream(b, func(w io.Writer) io.WriteCloser { return lz4.NewWriter(w) })
+		},
+		decompress: func(b []byte) ([]byte, error) {
+			return ioutil.ReadAll(lz4.NewReader(bytes.NewReader(b)))
+		},
+	},
+}
+
+var (
+	zstdOnce sync.Once
+	zstdEnc  *zstd.Encoder
+	zstdDec  *zstd.Decoder
+	zstdErr  error
+)
+
+// zstdEncoder returns the shared zstd encoder. EncodeAll is safe for concurrent use.
+func zstdEncoder() (*zstd.Encoder, error) {
+	zstdOnce.Do(initZstd)
+
+	return zstdEnc, zstdErr
+}
+
+// zstdDecoder returns the shared zstd decoder. DecodeAll is safe for concurrent use.
+func zstdDecoder() (*zstd.Decoder, error) {
+	zstdOnce.Do(initZstd)
+
+	return zstdDec, zstdErr
+}
+
+func initZstd() {
+	if zstdEnc, zstdErr = zstd.NewWriter(nil); zstdErr != nil {
+		return
+	}
+
+	zstdDec, zstdErr = zstd.NewReader(nil)
+}
+
+func compressStream(b []byte, newWriter func(io.Writer) io.WriteCloser) ([]byte, error) {
+	var buf bytes.Buffer
+	w := newWriter(&buf)
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// NewCompressionEncoder creates an encoder that compresses the output of the inner encoder.
+//
+// The compressed data is prefixed with a byte identifying the compression
+// algorithm, to be read by a compression decoder.
+// A nil inner encoder expects the data to be bytes.
+func NewCompressionEncoder(inner Encoder, c Compression) Encoder {
+	comp, ok := compressors[c]
+
+	return EncoderFunc(func(v interface{}) ([]byte, error) {
+		if !ok {
+			return nil, xerrors.Errorf("codec: unknown compression %d", c)
+		}
+
+		b, err := encode(inner, v)
+		if err != nil || b == nil {
+			return b, err
+		}
+
+		buf, err := comp.compress(b)
+		if err != nil {
+			return nil, err
+		}
+
+		return append([]byte{comp.id}, buf...), nil
+	})
+}
+
+// NewCompressionDecoder creates a decoder that decompresses the data before passing it to the inner decoder.
+//
+// The compression algorithm is read from the prefix written by a compression
+// encoder. Data with an unknown prefix is rejected.
+// A nil inner decoder returns the decompressed bytes.
+func NewCompressionDecoder(inner Decoder) Decoder {
+	return DecoderFunc(func(b []byte) (interface{}, error) {
+		if b == nil {
+			return decode(inner, nil)
+		}
+
+		if len(b) == 0 {
+			return nil, xerrors.New("codec: missing compression prefix")
+		}
+
+		for _, comp := range compressors {
+			if comp.id != b[0] {
+				continue
+			}
+
+			buf, err := comp.decompress(b[1:])
+			if err != nil {
+				return nil, err
+			}
+
+			return decode(inner, buf)
+		}
+
+		return nil, xerrors.Errorf("codec: unknown compression prefix %#x", b[0])
+	})
+}
+
+// NewGzipEncoder creates an encoder that gzip compresses the output of the inner encoder.
+//
+// The output is plain gzip data, without the prefix of a compression encoder.
+// A nil inner encoder expects the data to be bytes.
+func NewGzipEncoder(inner Encoder) Encoder {
+	return EncoderFunc(func(v interface{}) ([]byte, error) {
+		b, err := encode(inner, v)
+		if err != nil || b == nil {
+			return b, err
+		}
+
+		return compressors[Gzip].compress(b)
+	})
+}
+
+// NewGzipDecoder creates a decoder that gzip decompresses the data before passing it to the inner decoder.
+//
+// The data must be plain gzip data, without the prefix of a compression encoder.
+// A nil inner decoder returns the decompressed bytes.
+func NewGzipDecoder(inner Decoder) Decoder {
+	return DecoderFunc(func(b []byte) (interface{}, error) {
+		if b == nil {
+			return decode(inner, nil)
+		}
+
+		buf, err := compressors[Gzip].decompress(b)
+		if err != nil {
+			return nil, err
+		}
+
+		return decode(inner, buf)
+	})
+}
@@ -0,0 +1,130 @@
+package codec_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rafalmnich/streams/v6/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGzip_RoundTrip(t *testing.T) {
+	enc := codec.NewGzipEncoder(codec.StringEncoder{})
+	dec := codec.NewGzipDecoder(codec.StringDecoder{})
+
+	b, err := enc.Encode("foobar")
+	assert.NoError(t, err)
+	assert.NotEqual(t, []byte("foobar"), b)
+
+	got, err := dec.Decode(b)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "foobar", got)
+}
+
+func TestGzipEncoder_EncodeNil(t *testing.T) {
+	enc := codec.NewGzipEncoder(nil)
+
+	got, err := enc.Encode(nil)
+
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestGzipDecoder_DecodeError(t *testing.T) {
+	dec := codec.NewGzipDecoder(nil)
+
+	_, err := dec.Decode([]byte("foobar"))
+
+	assert.Error(t, err)
+}
+
+func TestCompression_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		comp   codec.Compression
+		prefix []byte
+	}{
+		{name: "Gzip", comp: codec.Gzip, prefix: []byte{0x01, 0x1f, 0x8b}},
+		{name: "Zstd", comp: codec.Zstd, prefix: []byte{0x02, 0x28, 0xb5, 0x2f, 0xfd}},
+		{name: "Snappy", comp: codec.Snappy, prefix: []byte{0x03, 0xff, 0x06, 0x00, 0x00}},
+		{name: "LZ4", comp: codec.LZ4, prefix: []byte{0x04, 0x04, 0x22, 0x4d, 0x18}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := bytes.Repeat([]byte("foobar"), 100)
+			enc := codec.NewCompressionEncoder(nil, tt.comp)
+			dec := codec.NewCompressionDecoder(nil)
+
+			b, err := enc.Encode(in)
+			assert.NoError(t, err)
+			assert.True(t, bytes.HasPrefix(b, tt.prefix))
+			assert.Less(t, len(b), len(in))
+
+			got, err := dec.Decode(b)
+
+			assert.NoError(t, err)
+			assert.Equal(t, in, got)
+		})
+	}
+}
+
+func TestCompressionEncoder_EncodeUnknownCompression(t *testing.T) {
+	enc := codec.NewCompressionEncoder(nil, codec.Compression(255))
+
+	_, err := enc.Encode([]byte("foobar"))
+
+	assert.Error(t, err)
+}
+
+func TestCompressionEncoder_EncodeNil(t *testing.T) {
+	enc := codec.NewCompressionEncoder(nil, codec.Zstd)
+
+	got, err := enc.Encode(nil)
+
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestCompressionDecoder_DecodeNil(t *testing.T) {
+	dec := codec.NewCompressionDecoder(nil)
+
+	got, err := dec.Decode(nil)
+
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestCompressionDecoder_DecodeUncompressed(t *testing.T) {
+	dec := codec.NewCompressionDecoder(codec.StringDecoder{})
+
+	_, err := dec.Decode([]byte("foobar"))
+
+	assert.Error(t, err)
+}
+
+func TestCompressionDecoder_DecodeEmpty(t *testing.T) {
+	dec := codec.NewCompressionDecoder(codec.StringDecoder{})
+
+	_, err := dec.Decode([]byte{})
+
+	assert.Error(t, err)
+}
+
+func TestCompressionDecoder_DecodeUnprefixedGzip(t *testing.T) {
+	b, _ := codec.NewGzipEncoder(nil).Encode([]byte("foobar"))
+	dec := codec.NewCompressionDecoder(nil)
+
+	_, err := dec.Decode(b)
+
+	assert.Error(t, err)
+}
+
+func TestCompressionDecoder_DecodeCorrupted(t *testing.T) {
+	dec := codec.NewCompressionDecoder(nil)
+
+	_, err := dec.Decode([]byte{0x02, 0x28, 0xb5, 0x2f, 0xfd, 0x00})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package codec
+
+import (
+	"encoding/json"
+
+	"golang.org/x/xerrors"
+)
+
+// envelope represents an encoded payload wrapped with its type.
+type envelope struct {
+	Type    string `json:"type"`
+	Payload []byte `json:"payload"`
+}
+
+// NewEnvelopeEncoder creates an encoder that wraps the output of the inner
+// encoder in a JSON envelope carrying the given type.
+//
+// A nil inner encoder expects the data to be bytes.
+func NewEnvelopeEncoder(typ string, inner Encoder) Encoder {
+	return EncoderFunc(func(v interface{}) ([]byte, error) {
+		b, err := encode(inner, v)
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(envelope{Type: typ, Payload: b})
+	})
+}
+
+// NewEnvelopeDecoder creates a decoder that unwraps a JSON envelope and
+// decodes the payload with the decoder registered for the envelope type.
+//
+// A nil registered decoder returns the payload bytes.
+func NewEnvelopeDecoder(decoders map[string]Decoder) Decoder {
+	return DecoderFunc(func(b []byte) (interface{}, error) {
+		var env envelope
+		if err := json.Unmarshal(b, &env); err != nil {
+			return nil, err
+		}
+
+		dec, ok := decoders[env.Type]
+		if !ok {
+			return nil, xerrors.Errorf("codec: unknown envelope type %q", env.Type)
+		}
+
+		return decode(dec, env.Payload)
+	})
+}
@@ -0,0 +1,58 @@
+package codec_test
+
+import (
+	"testing"
+
+	"github.com/rafalmnich/streams/v6/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvelopeEncoder_Encode(t *testing.T) {
+	enc := codec.NewEnvelopeEncoder("test", codec.StringEncoder{})
+
+	got, err := enc.Encode("foobar")
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"test","payload":"Zm9vYmFy"}`, string(got))
+}
+
+func TestEnvelopeDecoder_Decode(t *testing.T) {
+	dec := codec.NewEnvelopeDecoder(map[string]codec.Decoder{
+		"string": codec.StringDecoder{},
+		"bytes":  nil,
+	})
+
+	got, err := dec.Decode([]byte(`{"type":"string","payload":"Zm9vYmFy"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "foobar", got)
+
+	got, err = dec.Decode([]byte(`{"type":"bytes","payload":"Zm9vYmFy"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("foobar"), got)
+}
+
+func TestEnvelopeDecoder_DecodeError(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []byte
+	}{
+		{
+			name: "Invalid JSON",
+			in:   []byte(`{`),
+		},
+		{
+			name: "Unknown Type",
+			in:   []byte(`{"type":"other","payload":"Zm9vYmFy"}`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := codec.NewEnvelopeDecoder(map[string]codec.Decoder{"string": codec.StringDecoder{}})
+
+			_, err := dec.Decode(tt.in)
+
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package codec
+
+import (
+	"github.com/rafalmnich/streams/v6"
+	"golang.org/x/xerrors"
+)
+
+// EncodeMessage encodes the key and value of the Message.
+//
+// A nil encoder leaves the corresponding field untouched.
+func EncodeMessage(msg streams.Message, key, value Encoder) (streams.Message, error) {
+	if key != nil {
+		k, err := key.Encode(msg.Key)
+		if err != nil {
+			return msg, err
+		}
+		msg.Key = k
+	}
+
+	if value != nil {
+		v, err := value.Encode(msg.Value)
+		if err != nil {
+			return msg, err
+		}
+		msg.Value = v
+	}
+
+	return msg, nil
+}
+
+// DecodeMessage decodes the byte key and value of the Message.
+//
+// A nil decoder leaves the corresponding field untouched.
+func DecodeMessage(msg streams.Message, key, value Decoder) (streams.Message, error) {
+	if key != nil {
+		k, err := decodeField(key, msg.Key)
+		if err != nil {
+			return msg, err
+		}
+		msg.Key = k
+	}
+
+	if value != nil {
+		v, err := decodeField(value, msg.Value)
+		if err != nil {
+			return msg, err
+		}
+		msg.Value = v
+	}
+
+	return msg, nil
+}
+
+func decodeField(dec Decoder, v interface{}) (interface{}, error) {
+	switch b := v.(type) {
+	case nil:
+		return dec.Decode(nil)
+	case []byte:
+		return dec.Decode(b)
+	case string:
+		return dec.Decode([]byte(b))
+	default:
+		return nil, xerrors.Errorf("codec: cannot decode %T", v)
+	}
+}
@@ -0,0 +1,46 @@
+package codec_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeMessage(t *testing.T) {
+	msg := streams.NewMessage("foo", "bar")
+
+	got, err := codec.EncodeMessage(msg, codec.StringEncoder{}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("foo"), got.Key)
+	assert.Equal(t, "bar", got.Value)
+}
+
+func TestEncodeMessage_Error(t *testing.T) {
+	enc := codec.EncoderFunc(func(interface{}) ([]byte, error) {
+		return nil, errors.New("test")
+	})
+
+	_, err := codec.EncodeMessage(streams.NewMessage("foo", "bar"), nil, enc)
+
+	assert.Error(t, err)
+}
+
+func TestDecodeMessage(t *testing.T) {
+	msg := streams.NewMessage([]byte("foo"), "bar")
+
+	got, err := codec.DecodeMessage(msg, codec.StringDecoder{}, codec.ByteDecoder{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", got.Key)
+	assert.Equal(t, []byte("bar"), got.Value)
+}
+
+func TestDecodeMessage_Error(t *testing.T) {
+	_, err := codec.DecodeMessage(streams.NewMessage(1, nil), codec.StringDecoder{}, nil)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,423 @@
+// Package debug exposes live debug information of a running streams Task over HTTP.
+//
+// A Debugger collects the metrics flushed by the Task monitor and the errors of
+// its processors, and samples the messages flowing through a node on demand. It
+// is both the Stats and the Tracer of the Task:
+//
+//	d := debug.New()
+//	task := streams.NewTask(tp, streams.WithStats(d), streams.WithTracer(d))
+//	http.Handle("/debug/streams/", http.StripPrefix("/debug/streams", d.Handler(tp, task)))
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rafalmnich/streams/v6"
+)
+
+// DefaultErrorHistory is the default number of recent errors kept by the Debugger.
+const DefaultErrorHistory = 100
+
+// OptFunc represents a function that sets up the Debugger.
+type OptFunc func(d *Debugger)
+
+// WithStats sets the stats the metrics are forwarded to.
+func WithStats(stats streams.Stats) OptFunc {
+	return func(d *Debugger) {
+		d.stats = stats
+	}
+}
+
+// WithTracer sets the tracer the spans are forwarded to.
+func WithTracer(tracer streams.Tracer) OptFunc {
+	return func(d *Debugger) {
+		d.tracer = tracer
+	}
+}
+
+// WithErrorHistory sets the number of recent errors kept.
+func WithErrorHistory(n int) OptFunc {
+	return func(d *Debugger) {
+		d.history = n
+	}
+}
+
+// Latency represents the latency of processing a message in a node over the last interval.
+type Latency struct {
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P95  time.Duration `json:"p95"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+}
+
+// NodeStats represents the metrics of a node reported by the Task monitor.
+type NodeStats struct {
+	// Name is the name of the node.
+	Name string `json:"name"`
+	// Processed is the number of messages processed by the node.
+	Processed int64 `json:"processed"`
+	// Errors is the number of messages that failed processing in the node.
+	Errors int64 `json:"errors"`
+	// Latency is the latency of the node over the last interval it processed messages in.
+	Latency Latency `json:"latency"`
+	// BackPressure is the percentage of the buffer of the node in use, or -1 if unknown.
+	BackPressure float64 `json:"backPressure"`
+	// Updated is the time the metrics of the node were last reported.
+	Updated time.Time `json:"updated"`
+}
+
+// Error represents an error raised while processing a message in a node.
+type Error struct {
+	Time  time.Time `json:"time"`
+	Node  string    `json:"node"`
+	Error string    `json:"error"`
+}
+
+// Sample represents a message sampled from a node.
+type Sample struct {
+	Time  time.Time       `json:"time"`
+	Key   json.RawMessage `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+var (
+	_ = (streams.Stats)(&Debugger{})
+	_ = (streams.Tracer)(&Debugger{})
+)
+
+// Debugger collects debug information of a running Task.
+type Debugger struct {
+	stats   streams.Stats
+	tracer  streams.Tracer
+	history int
+
+	mu     sync.Mutex
+	nodes  map[string]*NodeStats
+	errors []Error
+	next   int
+
+	tracers sync.Map
+}
+
+// New creates a new Debugger.
+func New(opts ...OptFunc) *Debugger {
+	d := &Debugger{
+		history: DefaultErrorHistory,
+		nodes:   map[string]*NodeStats{},
+	}
+
+	for _, optFn := range opts {
+		optFn(d)
+	}
+
+	return d
+}
+
+// Nodes returns the metrics of the nodes, ordered by name.
+func (d *Debugger) Nodes() []NodeStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	nodes := make([]NodeStats, 0, len(d.nodes))
+	for _, n := range d.nodes {
+		nodes = append(nodes, *n)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	return nodes
+}
+
+// Errors returns the recent errors, newest first.
+func (d *Debugger) Errors() []Error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	errs := make([]Error, 0, len(d.errors))
+	for i := 1; i <= len(d.errors); i++ {
+		errs = append(errs, d.errors[(d.next-i+len(d.errors))%len(d.errors)])
+	}
+
+	return errs
+}
+
+// Sample samples up to n messages flowing through the named node, returning
+// once n messages are sampled or the context is done.
+func (d *Debugger) Sample(ctx context.Context, node string, n int) []Sample {
+	if n <= 0 {
+		return []Sample{}
+	}
+
+	s := &sampler{
+		n:    n,
+		msgs: make([]Sample, 0, n),
+		done: make(chan struct{}),
+	}
+
+	t := d.nodeTracer(node)
+	t.addSampler(s)
+	defer t.removeSampler(s)
+
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+	}
+
+	return s.samples()
+}
+
+// Inc increments a count by the value.
+func (d *Debugger) Inc(name string, value int64, tags ...interface{}) {
+	switch name {
+	case "node.throughput":
+		d.update(tags, func(n *NodeStats) { n.Processed += value })
+	case "node.errors":
+		d.update(tags, func(n *NodeStats) { n.Errors += value })
+	}
+
+	if d.stats != nil {
+		d.stats.Inc(name, value, tags...)
+	}
+}
+
+// Gauge measures the value of a metric.
+func (d *Debugger) Gauge(name string, value float64, tags ...interface{}) {
+	if name == "node.back-pressure" {
+		d.update(tags, func(n *NodeStats) { n.BackPressure = value })
+	}
+
+	if d.stats != nil {
+		d.stats.Gauge(name, value, tags...)
+	}
+}
+
+// Timing sends the value of a Duration.
+func (d *Debugger) Timing(name string, value time.Duration, tags ...interface{}) {
+	switch name {
+	case "node.latency":
+		d.update(tags, func(n *NodeStats) { n.Latency.Mean = value })
+	case "node.latency.p50":
+		d.update(tags, func(n *NodeStats) { n.Latency.P50 = value })
+	case "node.latency.p95":
+		d.update(tags, func(n *NodeStats) { n.Latency.P95 = value })
+	case "node.latency.p99":
+		d.update(tags, func(n *NodeStats) { n.Latency.P99 = value })
+	case "node.latency.max":
+		d.update(tags, func(n *NodeStats) { n.Latency.Max = value })
+	}
+
+	if d.stats != nil {
+		d.stats.Timing(name, value, tags...)
+	}
+}
+
+// update updates the metrics of the node named in the tags.
+func (d *Debugger) update(tags []interface{}, fn func(n *NodeStats)) {
+	name, ok := nodeName(tags)
+	if !ok {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	n, ok := d.nodes[name]
+	if !ok {
+		n = &NodeStats{Name: name, BackPressure: -1}
+		d.nodes[name] = n
+	}
+
+	fn(n)
+	n.Updated = time.Now()
+}
+
+// nodeName returns the value of the name tag.
+func nodeName(tags []interface{}) (string, bool) {
+	for i := 0; i+1 < len(tags); i += 2 {
+		if tags[i] == "name" {
+			name, ok := tags[i+1].(string)
+			return name, ok
+		}
+	}
+
+	return "", false
+}
+
+// Start starts a span of the processing of the Message in the named node.
+func (d *Debugger) Start(ctx context.Context, node string, msg streams.Message) (context.Context, streams.Span) {
+	t := d.nodeTracer(node)
+	if atomic.LoadInt32(&t.sampling) > 0 {
+		t.sample(msg)
+	}
+
+	if d.tracer == nil {
+		return ctx, t
+	}
+
+	ctx, span := d.tracer.Start(ctx, node, msg)
+	return ctx, &chainedSpan{tracer: t, next: span}
+}
+
+// recordError records an error raised in the named node.
+func (d *Debugger) recordError(node string, err error) {
+	if d.history <= 0 {
+		return
+	}
+
+	e := Error{
+		Time:  time.Now(),
+		Node:  node,
+		Error: err.Error(),
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.errors) < d.history {
+		d.errors = append(d.errors, e)
+		d.next = len(d.errors) % d.history
+		return
+	}
+
+	d.errors[d.next] = e
+	d.next = (d.next + 1) % d.history
+}
+
+// nodeTracer returns the tracer of the named node, creating it if needed.
+func (d *Debugger) nodeTracer(node string) *nodeTracer {
+	if t, ok := d.tracers.Load(node); ok {
+		return t.(*nodeTracer)
+	}
+
+	t, _ := d.tracers.LoadOrStore(node, &nodeTracer{debugger: d, node: node})
+	return t.(*nodeTracer)
+}
+
+// nodeTracer traces the processing of messages in a node.
+//
+// It is the span of every processing in the node, so tracing does not allocate.
+type nodeTracer struct {
+	sampling int32
+
+	debugger *Debugger
+	node     string
+
+	mu       sync.Mutex
+	samplers []*sampler
+}
+
+// End ends the span, recording the error if the processing failed.
+func (t *nodeTracer) End(err error) {
+	if err != nil {
+		t.debugger.recordError(t.node, err)
+	}
+}
+
+// addSampler starts sampling the messages of the node.
+func (t *nodeTracer) addSampler(s *sampler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.samplers = append(t.samplers, s)
+	atomic.AddInt32(&t.sampling, 1)
+}
+
+// removeSampler stops sampling the messages of the node.
+func (t *nodeTracer) removeSampler(s *sampler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, sampler := range t.samplers {
+		if sampler == s {
+			t.samplers = append(t.samplers[:i], t.samplers[i+1:]...)
+			atomic.AddInt32(&t.sampling, -1)
+			return
+		}
+	}
+}
+
+// sample adds the message to the samplers of the node.
+func (t *nodeTracer) sample(msg streams.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.samplers) == 0 {
+		return
+	}
+
+	s := Sample{
+		Time:  time.Now(),
+		Key:   printable(msg.Key),
+		Value: printable(msg.Value),
+	}
+	for _, sampler := range t.samplers {
+		sampler.add(s)
+	}
+}
+
+// chainedSpan ends the span of the node along with the span of the next tracer.
+type chainedSpan struct {
+	tracer *nodeTracer
+	next   streams.Span
+}
+
+// End ends the spans.
+func (s *chainedSpan) End(err error) {
+	s.tracer.End(err)
+	s.next.End(err)
+}
+
+// sampler collects the sampled messages of a Sample call.
+type sampler struct {
+	n int
+
+	mu   sync.Mutex
+	msgs []Sample
+	done chan struct{}
+}
+
+// add adds a sampled message, if the sampler is not full.
+func (s *sampler) add(msg Sample) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.msgs) >= s.n {
+		return
+	}
+
+	s.msgs = append(s.msgs, msg)
+	if len(s.msgs) == s.n {
+		close(s.done)
+	}
+}
+
+// samples returns the sampled messages.
+func (s *sampler) samples() []Sample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Sample{}, s.msgs...)
+}
+
+// printable encodes the value as JSON, as a string if it is bytes or cannot be encoded.
+func printable(v interface{}) json.RawMessage {
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+
+	if b, err := json.Marshal(v); err == nil {
+		return b
+	}
+
+	b, _ := json.Marshal(fmt.Sprintf("%v", v))
+	return b
+}
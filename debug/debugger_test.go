@@ -0,0 +1,233 @@
+package debug_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/channel"
+	"github.com/rafalmnich/streams/v6/debug"
+	"github.com/rafalmnich/streams/v6/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugger_Nodes(t *testing.T) {
+	d := debug.New()
+
+	d.Inc("node.throughput", 3, "name", "b")
+	d.Inc("node.throughput", 2, "name", "b")
+	d.Inc("node.errors", 1, "name", "b")
+	d.Timing("node.latency", 2*time.Millisecond, "name", "b")
+	d.Timing("node.latency.p50", time.Millisecond, "name", "b")
+	d.Timing("node.latency.p95", 3*time.Millisecond, "name", "b")
+	d.Timing("node.latency.p99", 4*time.Millisecond, "name", "b")
+	d.Timing("node.latency.max", 5*time.Millisecond, "name", "b")
+	d.Gauge("node.back-pressure", 50, "name", "b")
+	d.Inc("node.throughput", 1, "name", "a")
+	d.Inc("commit.commits", 1, "name", "streams:commit")
+	d.Gauge("monitor.back-pressure", 10)
+
+	nodes := d.Nodes()
+
+	if assert.Len(t, nodes, 2) {
+		assert.Equal(t, "a", nodes[0].Name)
+		assert.Equal(t, int64(1), nodes[0].Processed)
+		assert.Equal(t, float64(-1), nodes[0].BackPressure)
+		assert.Equal(t, "b", nodes[1].Name)
+		assert.Equal(t, int64(5), nodes[1].Processed)
+		assert.Equal(t, int64(1), nodes[1].Errors)
+		assert.Equal(t, debug.Latency{
+			Mean: 2 * time.Millisecond,
+			P50:  time.Millisecond,
+			P95:  3 * time.Millisecond,
+			P99:  4 * time.Millisecond,
+			Max:  5 * time.Millisecond,
+		}, nodes[1].Latency)
+		assert.Equal(t, float64(50), nodes[1].BackPressure)
+		assert.False(t, nodes[1].Updated.IsZero())
+	}
+}
+
+func TestDebugger_ForwardsStats(t *testing.T) {
+	stats := mocks.NewStats(t)
+	stats.ExpectInc("node.throughput", int64(1), "name", "test")
+	stats.ExpectGauge("node.back-pressure", float64(10), "name", "test")
+	stats.ExpectTiming("node.latency", time.Second, "name", "test")
+	d := debug.New(debug.WithStats(stats))
+
+	d.Inc("node.throughput", 1, "name", "test")
+	d.Gauge("node.back-pressure", 10, "name", "test")
+	d.Timing("node.latency", time.Second, "name", "test")
+
+	stats.AssertExpectations()
+}
+
+func TestDebugger_Errors(t *testing.T) {
+	d := debug.New(debug.WithErrorHistory(2))
+
+	for i, node := range []string{"a", "b", "c"} {
+		_, span := d.Start(context.Background(), node, streams.NewMessage(nil, i))
+		span.End(errors.New("error " + node))
+	}
+	_, span := d.Start(context.Background(), "d", streams.NewMessage(nil, 4))
+	span.End(nil)
+
+	errs := d.Errors()
+
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, "c", errs[0].Node)
+		assert.Equal(t, "error c", errs[0].Error)
+		assert.Equal(t, "b", errs[1].Node)
+		assert.False(t, errs[1].Time.IsZero())
+	}
+}
+
+func TestDebugger_ErrorsInSyncModeRecordedInRaisingNode(t *testing.T) {
+	in := make(chan streams.Message, 1)
+	b := streams.NewStreamBuilder()
+	b.Source("src", channel.NewSource(in)).
+		MapFunc("parent", func(msg streams.Message) (streams.Message, error) {
+			return msg, nil
+		}).
+		MapFunc("leaf", func(msg streams.Message) (streams.Message, error) {
+			return msg, errors.New("test")
+		})
+	tp, _ := b.Build()
+	d := debug.New()
+	task := streams.NewTask(tp,
+		streams.WithMode(streams.Sync),
+		streams.WithStats(d),
+		streams.WithTracer(d),
+		streams.WithMonitorInterval(time.Millisecond),
+	)
+	errs := make(chan error, 1)
+	task.OnError(func(err error) {
+		errs <- err
+	})
+	if err := task.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	in <- streams.NewMessage(nil, 1)
+	select {
+	case <-errs:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the error handler to be called")
+	}
+	_ = task.Close()
+
+	got := d.Errors()
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, "leaf", got[0].Node)
+		assert.Equal(t, "test", got[0].Error)
+	}
+	for _, n := range d.Nodes() {
+		switch n.Name {
+		case "leaf":
+			assert.Equal(t, int64(1), n.Errors)
+		default:
+			assert.Equal(t, int64(0), n.Errors, n.Name)
+		}
+	}
+}
+
+func TestDebugger_ErrorsWithoutHistory(t *testing.T) {
+	d := debug.New(debug.WithErrorHistory(0))
+
+	_, span := d.Start(context.Background(), "a", streams.NewMessage(nil, 1))
+	span.End(errors.New("test"))
+
+	assert.Len(t, d.Errors(), 0)
+}
+
+func TestDebugger_Sample(t *testing.T) {
+	d := debug.New()
+
+	done := make(chan []debug.Sample)
+	go func() {
+		done <- d.Sample(context.Background(), "test", 2)
+	}()
+
+	deadline := time.After(5 * time.Second)
+	for i := 0; ; i++ {
+		select {
+		case samples := <-done:
+			if assert.Len(t, samples, 2) {
+				assert.JSONEq(t, `"key"`, string(samples[0].Key))
+				assert.JSONEq(t, `{"id":1}`, string(samples[1].Value))
+			}
+			return
+
+		case <-deadline:
+			t.Fatal("sampling did not complete")
+
+		default:
+			_, _ = d.Start(context.Background(), "other", streams.NewMessage("other", nil))
+			_, _ = d.Start(context.Background(), "test", streams.NewMessage([]byte("key"), map[string]int{"id": 1}))
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestDebugger_SampleTimesOut(t *testing.T) {
+	d := debug.New()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	samples := d.Sample(ctx, "test", 2)
+
+	assert.Len(t, samples, 0)
+}
+
+func TestDebugger_SampleUnencodableValue(t *testing.T) {
+	d := debug.New()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	go func() {
+		for ctx.Err() == nil {
+			_, _ = d.Start(context.Background(), "test", streams.NewMessage(nil, func() {}))
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	samples := d.Sample(ctx, "test", 1)
+
+	if assert.Len(t, samples, 1) {
+		assert.Equal(t, "null", string(samples[0].Key))
+		assert.Contains(t, string(samples[0].Value), "0x")
+	}
+}
+
+func TestDebugger_StartChainsTracer(t *testing.T) {
+	next := &fakeTracer{}
+	d := debug.New(debug.WithTracer(next))
+	parent := context.WithValue(context.Background(), ctxKey{}, "parent")
+
+	ctx, span := d.Start(parent, "test", streams.NewMessage(nil, 1))
+	span.End(errors.New("test"))
+
+	assert.Equal(t, "test", ctx.Value(ctxKey{}))
+	assert.Equal(t, "test", next.node)
+	assert.EqualError(t, next.err, "test")
+	assert.Len(t, d.Errors(), 1)
+}
+
+type ctxKey struct{}
+
+type fakeTracer struct {
+	node string
+	err  error
+}
+
+func (t *fakeTracer) Start(ctx context.Context, node string, msg streams.Message) (context.Context, streams.Span) {
+	t.node = node
+
+	return context.WithValue(ctx, ctxKey{}, node), t
+}
+
+func (t *fakeTracer) End(err error) {
+	t.err = err
+}
@@ -0,0 +1,160 @@
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rafalmnich/streams/v6"
+)
+
+// Sampling defaults and limits of the sample endpoint.
+const (
+	DefaultSampleSize    = 10
+	DefaultSampleTimeout = 10 * time.Second
+	MaxSampleSize        = 1000
+	MaxSampleTimeout     = time.Minute
+)
+
+// node represents the debug information of a node.
+type node struct {
+	NodeStats
+
+	Source        bool `json:"source"`
+	QueueDepth    int  `json:"queueDepth"`
+	QueueCapacity int  `json:"queueCapacity"`
+}
+
+type handler struct {
+	debugger *Debugger
+	topology *streams.Topology
+	task     streams.ManagedTask
+}
+
+// Handler returns an http.Handler exposing the debug information of the Task
+// running the topology.
+//
+// The handler serves:
+//
+//	/topology          the description of the topology
+//	/status            the status of the Task
+//	/nodes             the metrics and pump queue lengths of every node
+//	/errors            the recent processing errors, newest first
+//	/sample?node=name  samples the next messages processed by a processor node,
+//	                   up to n (default 10) within timeout (default 10s)
+func (d *Debugger) Handler(tp *streams.Topology, task streams.ManagedTask) http.Handler {
+	h := &handler{debugger: d, topology: tp, task: task}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/topology", h.describe)
+	mux.HandleFunc("/status", h.status)
+	mux.HandleFunc("/nodes", h.nodes)
+	mux.HandleFunc("/errors", h.errors)
+	mux.HandleFunc("/sample", h.sample)
+
+	return mux
+}
+
+func (h *handler) describe(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, h.topology.Describe())
+}
+
+func (h *handler) status(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, h.task.Status())
+}
+
+func (h *handler) nodes(w http.ResponseWriter, _ *http.Request) {
+	desc := h.topology.Describe()
+
+	stats := map[string]NodeStats{}
+	for _, n := range h.debugger.Nodes() {
+		stats[n.Name] = n
+	}
+
+	queues := map[string]streams.NodeStatus{}
+	for _, n := range h.task.Status().Nodes {
+		queues[n.Name] = n
+	}
+
+	nodes := make([]node, 0, len(desc.Sources)+len(desc.Processors))
+	for _, descs := range [][]streams.NodeDescription{desc.Sources, desc.Processors} {
+		for _, d := range descs {
+			n := node{
+				NodeStats:     NodeStats{Name: d.Name, BackPressure: -1},
+				Source:        len(nodes) < len(desc.Sources),
+				QueueDepth:    queues[d.Name].QueueDepth,
+				QueueCapacity: queues[d.Name].QueueCapacity,
+			}
+			if s, ok := stats[d.Name]; ok {
+				n.NodeStats = s
+			}
+
+			nodes = append(nodes, n)
+		}
+	}
+
+	writeJSON(w, nodes)
+}
+
+func (h *handler) errors(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, h.debugger.Errors())
+}
+
+func (h *handler) sample(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	name := q.Get("node")
+	if !h.isProcessor(name) {
+		http.Error(w, "debug: unknown processor node "+strconv.Quote(name), http.StatusNotFound)
+		return
+	}
+
+	n := DefaultSampleSize
+	if v := q.Get("n"); v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil || i <= 0 || i > MaxSampleSize {
+			http.Error(w, "debug: invalid sample size "+strconv.Quote(v), http.StatusBadRequest)
+			return
+		}
+		n = i
+	}
+
+	timeout := DefaultSampleTimeout
+	if v := q.Get("timeout"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 || d > MaxSampleTimeout {
+			http.Error(w, "debug: invalid sample timeout "+strconv.Quote(v), http.StatusBadRequest)
+			return
+		}
+		timeout = d
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	writeJSON(w, h.debugger.Sample(ctx, name, n))
+}
+
+// isProcessor determines if the name is the name of a processor node.
+func (h *handler) isProcessor(name string) bool {
+	for _, n := range h.topology.Processors() {
+		if n.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
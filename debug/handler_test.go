@@ -0,0 +1,181 @@
+package debug_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/channel"
+	"github.com/rafalmnich/streams/v6/debug"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHandler(t *testing.T) (http.Handler, *debug.Debugger, chan streams.Message) {
+	t.Helper()
+
+	in := make(chan streams.Message, 10)
+	out := make(chan streams.Message, 100)
+
+	b := streams.NewStreamBuilder()
+	b.Source("src", channel.NewSource(in)).
+		MapFunc("double", func(msg streams.Message) (streams.Message, error) {
+			msg.Value = msg.Value.(int) * 2
+			return msg, nil
+		}).
+		Process("sink", channel.NewSink(out, 1))
+	tp, errs := b.Build()
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	d := debug.New()
+	task := streams.NewTask(tp,
+		streams.WithStats(d),
+		streams.WithTracer(d),
+		streams.WithMonitorInterval(time.Millisecond),
+	)
+	if err := task.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = task.Close()
+	})
+
+	return d.Handler(tp, task), d, in
+}
+
+func get(t *testing.T, h http.Handler, path string, v interface{}) int {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	if rec.Code == http.StatusOK {
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return rec.Code
+}
+
+func TestHandler_Topology(t *testing.T) {
+	h, _, _ := newTestHandler(t)
+
+	var desc streams.TopologyDescription
+	code := get(t, h, "/topology", &desc)
+
+	assert.Equal(t, http.StatusOK, code)
+	if assert.Len(t, desc.Sources, 1) {
+		assert.Equal(t, "src", desc.Sources[0].Name)
+	}
+	assert.Len(t, desc.Processors, 2)
+}
+
+func TestHandler_Status(t *testing.T) {
+	h, _, _ := newTestHandler(t)
+
+	var status struct {
+		State string `json:"state"`
+	}
+	code := get(t, h, "/status", &status)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "running", status.State)
+}
+
+func TestHandler_Nodes(t *testing.T) {
+	h, _, in := newTestHandler(t)
+
+	in <- streams.NewMessage(nil, 1)
+
+	var nodes []struct {
+		Name          string `json:"name"`
+		Source        bool   `json:"source"`
+		Processed     int64  `json:"processed"`
+		QueueCapacity int    `json:"queueCapacity"`
+	}
+	assert.Eventually(t, func() bool {
+		code := get(t, h, "/nodes", &nodes)
+		return code == http.StatusOK && len(nodes) == 3 && nodes[2].Processed == 1
+	}, 5*time.Second, time.Millisecond)
+
+	assert.Equal(t, "src", nodes[0].Name)
+	assert.True(t, nodes[0].Source)
+	assert.Equal(t, "double", nodes[1].Name)
+	assert.False(t, nodes[1].Source)
+	assert.True(t, nodes[1].QueueCapacity > 0)
+	assert.Equal(t, "sink", nodes[2].Name)
+}
+
+func TestHandler_Errors(t *testing.T) {
+	h, d, _ := newTestHandler(t)
+	_, span := d.Start(context.Background(), "double", streams.NewMessage(nil, 1))
+	span.End(errors.New("test error"))
+
+	var errs []debug.Error
+	code := get(t, h, "/errors", &errs)
+
+	assert.Equal(t, http.StatusOK, code)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, "double", errs[0].Node)
+		assert.Equal(t, "test error", errs[0].Error)
+	}
+}
+
+func TestHandler_Sample(t *testing.T) {
+	h, _, in := newTestHandler(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for i := 1; ctx.Err() == nil; i++ {
+			select {
+			case in <- streams.NewMessage(nil, i):
+			case <-ctx.Done():
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	var samples []debug.Sample
+	code := get(t, h, "/sample?node=sink&n=3&timeout=5s", &samples)
+
+	assert.Equal(t, http.StatusOK, code)
+	if assert.Len(t, samples, 3) {
+		var v int
+		assert.NoError(t, json.Unmarshal(samples[0].Value, &v))
+		assert.True(t, v%2 == 0, "sampled %d before it was doubled", v)
+	}
+}
+
+func TestHandler_SampleInvalid(t *testing.T) {
+	h, _, _ := newTestHandler(t)
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{path: "/sample", code: http.StatusNotFound},
+		{path: "/sample?node=src", code: http.StatusNotFound},
+		{path: "/sample?node=nope", code: http.StatusNotFound},
+		{path: "/sample?node=sink&n=0", code: http.StatusBadRequest},
+		{path: "/sample?node=sink&n=1001", code: http.StatusBadRequest},
+		{path: "/sample?node=sink&timeout=forever", code: http.StatusBadRequest},
+		{path: "/sample?node=sink&timeout=1h", code: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			code := get(t, h, tt.path, nil)
+
+			assert.Equal(t, tt.code, code)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package streams
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NodeDescription represents the description of a topology node.
+type NodeDescription struct {
+	// Name is the name of the node.
+	Name string `json:"name"`
+	// Type is the type of the source or processor of the node.
+	Type string `json:"type"`
+	// Children are the names of the children of the node.
+	Children []string `json:"children"`
+	// Committer indicates that the processor of the node is a Committer.
+	Committer bool `json:"committer"`
+}
+
+// TopologyDescription represents a structured description of a Topology.
+type TopologyDescription struct {
+	// Sources are the source nodes, ordered by name.
+	Sources []NodeDescription `json:"sources"`
+	// Processors are the processor nodes, in the order they were added.
+	Processors []NodeDescription `json:"processors"`
+}
+
+// Describe returns a structured description of the topology.
+func (t Topology) Describe() TopologyDescription {
+	desc := TopologyDescription{
+		Sources:    make([]NodeDescription, 0, len(t.sources)),
+		Processors: make([]NodeDescription, 0, len(t.processors)),
+	}
+
+	for source, node := range t.sources {
+		desc.Sources = append(desc.Sources, describeNode(node, source))
+	}
+	sort.Slice(desc.Sources, func(i, j int) bool {
+		return desc.Sources[i].Name < desc.Sources[j].Name
+	})
+
+	for _, node := range t.processors {
+		desc.Processors = append(desc.Processors, describeNode(node, node.Processor()))
+	}
+
+	return desc
+}
+
+// describeNode describes a node with the given source or processor.
+func describeNode(node Node, v interface{}) NodeDescription {
+	children := make([]string, 0, len(node.Children()))
+	for _, child := range node.Children() {
+		children = append(children, child.Name())
+	}
+
+	_, isCommitter := node.Processor().(Committer)
+
+	return NodeDescription{
+		Name:      node.Name(),
+		Type:      fmt.Sprintf("%T", v),
+		Children:  children,
+		Committer: isCommitter,
+	}
+}
+
+// JSON renders the description as JSON.
+func (d TopologyDescription) JSON() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+// DOT renders the description as a Graphviz DOT digraph.
+//
+// Sources are drawn as boxes and committers with a double outline.
+func (d TopologyDescription) DOT() string {
+	var sb strings.Builder
+
+	sb.WriteString("digraph topology {\n")
+	sb.WriteString("\trankdir=LR;\n")
+
+	for _, n := range d.Sources {
+		fmt.Fprintf(&sb, "\t%s [shape=box, label=%s];\n", strconv.Quote(n.Name), strconv.Quote(n.Name+"\n"+n.Type))
+	}
+	for _, n := range d.Processors {
+		attrs := "shape=ellipse"
+		if n.Committer {
+			attrs += ", peripheries=2"
+		}
+
+		fmt.Fprintf(&sb, "\t%s [%s, label=%s];\n", strconv.Quote(n.Name), attrs, strconv.Quote(n.Name+"\n"+n.Type))
+	}
+
+	for _, n := range d.nodes() {
+		for _, child := range n.Children {
+			fmt.Fprintf(&sb, "\t%s -> %s;\n", strconv.Quote(n.Name), strconv.Quote(child))
+		}
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// Mermaid renders the description as a Mermaid flowchart.
+//
+// Sources are drawn as stadiums and committers as subroutines.
+func (d TopologyDescription) Mermaid() string {
+	var sb strings.Builder
+
+	sb.WriteString("graph LR\n")
+
+	ids := map[string]string{}
+	for i, n := range d.Sources {
+		id := "s" + strconv.Itoa(i)
+		ids[n.Name] = id
+
+		fmt.Fprintf(&sb, "\t%s([%s])\n", id, mermaidLabel(n))
+	}
+	for i, n := range d.Processors {
+		id := "p" + strconv.Itoa(i)
+		if _, ok := ids[n.Name]; !ok {
+			ids[n.Name] = id
+		}
+
+		if n.Committer {
+			fmt.Fprintf(&sb, "\t%s[[%s]]\n", id, mermaidLabel(n))
+			continue
+		}
+		fmt.Fprintf(&sb, "\t%s[%s]\n", id, mermaidLabel(n))
+	}
+
+	for _, n := range d.nodes() {
+		for _, child := range n.Children {
+			fmt.Fprintf(&sb, "\t%s --> %s\n", ids[n.Name], ids[child])
+		}
+	}
+
+	return sb.String()
+}
+
+// nodes returns the source and processor nodes.
+func (d TopologyDescription) nodes() []NodeDescription {
+	nodes := make([]NodeDescription, 0, len(d.Sources)+len(d.Processors))
+	nodes = append(nodes, d.Sources...)
+	return append(nodes, d.Processors...)
+}
+
+// mermaidLabel returns the quoted Mermaid label of a node.
+func mermaidLabel(n NodeDescription) string {
+	label := n.Name + "<br/>" + n.Type
+
+	return `"` + strings.ReplaceAll(label, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,104 @@
+package streams_test
+
+import (
+	"testing"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDescribeTopology() *streams.Topology {
+	b := streams.NewStreamBuilder()
+	s := b.Source("src", &fakeSource{})
+	pred := func(streams.Message) (bool, error) {
+		return true, nil
+	}
+	branches := s.BranchFunc("branch", pred, pred)
+	branches[0].MapFunc("map", passThroughMapper)
+	branches[1].Process("commit", &MockCommitter{})
+
+	tp, _ := b.Build()
+
+	return tp
+}
+
+func TestTopology_Describe(t *testing.T) {
+	tp := newDescribeTopology()
+
+	desc := tp.Describe()
+
+	assert.Equal(t, streams.TopologyDescription{
+		Sources: []streams.NodeDescription{
+			{Name: "src", Type: "*streams_test.fakeSource", Children: []string{"branch"}},
+		},
+		Processors: []streams.NodeDescription{
+			{Name: "branch", Type: "*streams.BranchProcessor", Children: []string{"map", "commit"}},
+			{Name: "map", Type: "*streams.MapProcessor", Children: []string{}},
+			{Name: "commit", Type: "*streams_test.MockCommitter", Children: []string{}, Committer: true},
+		},
+	}, desc)
+}
+
+func TestTopologyDescription_JSON(t *testing.T) {
+	desc := newDescribeTopology().Describe()
+
+	b, err := desc.JSON()
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"sources": [
+			{"name": "src", "type": "*streams_test.fakeSource", "children": ["branch"], "committer": false}
+		],
+		"processors": [
+			{"name": "branch", "type": "*streams.BranchProcessor", "children": ["map", "commit"], "committer": false},
+			{"name": "map", "type": "*streams.MapProcessor", "children": [], "committer": false},
+			{"name": "commit", "type": "*streams_test.MockCommitter", "children": [], "committer": true}
+		]
+	}`, string(b))
+}
+
+func TestTopologyDescription_DOT(t *testing.T) {
+	desc := newDescribeTopology().Describe()
+
+	dot := desc.DOT()
+
+	want := `digraph topology {
+	rankdir=LR;
+	"src" [shape=box, label="src\n*streams_test.fakeSource"];
+	"branch" [shape=ellipse, label="branch\n*streams.BranchProcessor"];
+	"map" [shape=ellipse, label="map\n*streams.MapProcessor"];
+	"commit" [shape=ellipse, peripheries=2, label="commit\n*streams_test.MockCommitter"];
+	"src" -> "branch";
+	"branch" -> "map";
+	"branch" -> "commit";
+}
+`
+	assert.Equal(t, want, dot)
+}
+
+func TestTopologyDescription_Mermaid(t *testing.T) {
+	desc := newDescribeTopology().Describe()
+
+	mermaid := desc.Mermaid()
+
+	want := `graph LR
+	s0(["src<br/>*streams_test.fakeSource"])
+	p0["branch<br/>*streams.BranchProcessor"]
+	p1["map<br/>*streams.MapProcessor"]
+	p2[["commit<br/>*streams_test.MockCommitter"]]
+	s0 --> p0
+	p0 --> p1
+	p0 --> p2
+`
+	assert.Equal(t, want, mermaid)
+}
+
+func TestTopologyDescription_MermaidEscapesQuotes(t *testing.T) {
+	desc := streams.TopologyDescription{
+		Processors: []streams.NodeDescription{{Name: `say "hi"`, Type: "test"}},
+	}
+
+	mermaid := desc.Mermaid()
+
+	assert.Equal(t, "graph LR\n\tp0[\"say #quot;hi#quot;<br/>test\"]\n", mermaid)
+}
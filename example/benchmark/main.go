@@ -5,9 +5,6 @@ import (
 	"log"
 	"net/http"
 	_ "net/http/pprof"
-	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/rafalmnich/streams/v6"
 )
@@ -32,8 +29,7 @@ func main() {
 	task.Start(ctx)
 	defer task.Close()
 
-	// Wait for SIGTERM
-	waitForShutdown()
+	streams.WaitForSignal(ctx)
 }
 
 func task(_ context.Context) (streams.Task, error) {
@@ -109,13 +105,3 @@ func (p *commitProcessor) Commit(ctx context.Context) error {
 func (p *commitProcessor) Close() error {
 	return nil
 }
-
-// waitForShutdown blocks until a SIGINT or SIGTERM is received.
-func waitForShutdown() {
-	quit := make(chan os.Signal)
-
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	defer signal.Stop(quit)
-
-	<-quit
-}
@@ -4,9 +4,6 @@ import (
 	"context"
 	"log"
 	"math/rand"
-	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/rafalmnich/streams/v6"
 )
@@ -39,8 +36,7 @@ func main() {
 	task.Start(ctx)
 	defer task.Close()
 
-	// Wait for SIGTERM
-	waitForShutdown()
+	streams.WaitForSignal(ctx)
 }
 
 type randIntSource struct {
@@ -120,13 +116,3 @@ func negativeMapper(msg streams.Message) (streams.Message, error) {
 
 	return msg, nil
 }
-
-// waitForShutdown blocks until a SIGINT or SIGTERM is received.
-func waitForShutdown() {
-	quit := make(chan os.Signal)
-
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	defer signal.Stop(quit)
-
-	<-quit
-}
@@ -4,10 +4,8 @@ import (
 	"context"
 	"log"
 	"math/rand"
-	"os"
-	"os/signal"
 	"strconv"
-	"syscall"
+	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/rafalmnich/streams/v6"
@@ -43,8 +41,7 @@ func main() {
 	tasks.Start(ctx)
 	defer tasks.Close()
 
-	// Wait for SIGTERM
-	waitForShutdown()
+	streams.WaitForSignal(ctx)
 }
 
 func producerTask(brokers []string, c *sarama.Config) (streams.Task, error) {
@@ -82,7 +79,11 @@ func consumerTask(brokers []string, c *sarama.Config) (streams.Task, error) {
 	config.GroupID = "example-consumer"
 	config.ValueDecoder = kafka.StringDecoder{}
 
-	src, err := kafka.NewSource(config)
+	newSource := func() (streams.Source, error) {
+		return kafka.NewSource(config)
+	}
+
+	src, err := newSource()
 	if err != nil {
 		return nil, err
 	}
@@ -95,7 +96,17 @@ func consumerTask(brokers []string, c *sarama.Config) (streams.Task, error) {
 		Process("commit-sink", sink)
 
 	tp, _ := builder.Build()
-	task := streams.NewTask(tp, streams.WithMode(Mode))
+	task := streams.NewTask(tp,
+		streams.WithMode(Mode),
+		streams.WithRestartPolicy(streams.RestartPolicy{
+			MaxRestarts: 5,
+			MinBackoff:  time.Second,
+			MaxBackoff:  time.Minute,
+			Sources: map[string]streams.SourceFactory{
+				"kafka-source": newSource,
+			},
+		}),
+	)
 	task.OnError(func(err error) {
 		log.Fatal(err.Error())
 	})
@@ -180,13 +191,3 @@ func (p *commitProcessor) Commit(ctx context.Context) error {
 func (p *commitProcessor) Close() error {
 	return nil
 }
-
-// waitForShutdown blocks until a SIGINT or SIGTERM is received.
-func waitForShutdown() {
-	quit := make(chan os.Signal)
-
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	defer signal.Stop(quit)
-
-	<-quit
-}
@@ -4,9 +4,6 @@ import (
 	"context"
 	"log"
 	"math/rand"
-	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/rafalmnich/streams/v6"
 )
@@ -26,8 +23,7 @@ func main() {
 	task.Start(context.Background())
 	defer task.Close()
 
-	// Wait for SIGTERM
-	waitForShutdown()
+	streams.WaitForSignal(context.Background())
 }
 
 type randIntSource struct {
@@ -64,13 +60,3 @@ func doubleMapper(msg streams.Message) (streams.Message, error) {
 
 	return msg, nil
 }
-
-// waitForShutdown blocks until a SIGINT or SIGTERM is received.
-func waitForShutdown() {
-	quit := make(chan os.Signal)
-
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	defer signal.Stop(quit)
-
-	<-quit
-}
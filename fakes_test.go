@@ -50,6 +50,18 @@ func (m *fakeMetadata) Merge(v streams.Metadata, s streams.MetadataStrategy) str
 	return m
 }
 
+type offsetMetadata int
+
+func (offsetMetadata) WithOrigin(streams.MetadataOrigin) {}
+
+func (m offsetMetadata) Merge(v streams.Metadata, s streams.MetadataStrategy) streams.Metadata {
+	if o, ok := v.(offsetMetadata); ok && o > m {
+		return o
+	}
+
+	return m
+}
+
 type fakeMetastore struct {
 	Metadata map[streams.Processor]streams.Metaitems
 }
@@ -124,8 +136,34 @@ type fakeMonitor struct{}
 
 func (*fakeMonitor) Processed(name string, l time.Duration, bp float64) {}
 
+func (*fakeMonitor) Failed(name string) {}
+
 func (*fakeMonitor) Committed(l time.Duration) {}
 
 func (*fakeMonitor) Close() error {
 	return nil
 }
+
+type recordingProcessor struct {
+	mu   sync.Mutex
+	msgs map[interface{}][]interface{}
+}
+
+func newRecordingProcessor() *recordingProcessor {
+	return &recordingProcessor{msgs: map[interface{}][]interface{}{}}
+}
+
+func (*recordingProcessor) WithPipe(streams.Pipe) {}
+
+func (p *recordingProcessor) Process(msg streams.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.msgs[msg.Key] = append(p.msgs[msg.Key], msg.Value)
+
+	return nil
+}
+
+func (*recordingProcessor) Close() error {
+	return nil
+}
@@ -0,0 +1,124 @@
+package file
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/codec"
+	"golang.org/x/xerrors"
+)
+
+// SinkOptFunc represents a function that sets up the Sink.
+type SinkOptFunc func(s *Sink)
+
+// WithValueEncoder sets the encoder used on the Message value before it is written.
+func WithValueEncoder(enc codec.Encoder) SinkOptFunc {
+	return func(s *Sink) {
+		s.valueEncoder = enc
+	}
+}
+
+// Sink represents a sink that writes Message values as lines of a file.
+//
+// The values must be []byte or string, unless a value encoder is set.
+// Lines are buffered and flushed to the file on commit.
+type Sink struct {
+	pipe streams.Pipe
+
+	w *bufio.Writer
+	c io.Closer
+
+	valueEncoder codec.Encoder
+
+	batch int
+	count int
+}
+
+// NewSink creates a new file Sink, appending to the file at the given path.
+//
+// A batch size of 0 will never commit.
+func NewSink(path string, batch int, opts ...SinkOptFunc) (*Sink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := NewWriterSink(f, batch, opts...)
+	s.c = f
+
+	return s, nil
+}
+
+// NewWriterSink creates a new file Sink, writing to the given writer.
+//
+// A batch size of 0 will never commit.
+func NewWriterSink(w io.Writer, batch int, opts ...SinkOptFunc) *Sink {
+	s := &Sink{
+		w:     bufio.NewWriter(w),
+		batch: batch,
+	}
+
+	for _, optFn := range opts {
+		optFn(s)
+	}
+
+	return s
+}
+
+// WithPipe sets the pipe on the Processor.
+func (s *Sink) WithPipe(pipe streams.Pipe) {
+	s.pipe = pipe
+}
+
+// Process processes the stream Message.
+func (s *Sink) Process(msg streams.Message) error {
+	out, err := codec.EncodeMessage(msg, nil, s.valueEncoder)
+	if err != nil {
+		return err
+	}
+
+	switch v := out.Value.(type) {
+	case []byte:
+		_, err = s.w.Write(v)
+	case string:
+		_, err = s.w.WriteString(v)
+	default:
+		return xerrors.Errorf("file: cannot write %T", out.Value)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.w.WriteByte('\n'); err != nil {
+		return err
+	}
+
+	s.count++
+	if s.batch > 0 && s.count >= s.batch {
+		s.count = 0
+		return s.pipe.Commit(msg)
+	}
+
+	return s.pipe.Mark(msg)
+}
+
+// Commit flushes the written lines to the file.
+func (s *Sink) Commit(ctx context.Context) error {
+	return s.w.Flush()
+}
+
+// Close flushes the written lines and closes the file.
+func (s *Sink) Close() error {
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+
+	if s.c == nil {
+		return nil
+	}
+
+	return s.c.Close()
+}
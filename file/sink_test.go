@@ -0,0 +1,111 @@
+package file_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/codec"
+	"github.com/rafalmnich/streams/v6/file"
+	"github.com/rafalmnich/streams/v6/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "streams")
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "test.txt")
+	if err := ioutil.WriteFile(path, []byte("foo\n"), 0644); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	sink, err := file.NewSink(path, 1)
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	pipe := mocks.NewPipe(t)
+	pipe.ExpectCommit()
+	sink.WithPipe(pipe)
+
+	assert.NoError(t, sink.Process(streams.NewMessage(nil, "bar")))
+	assert.NoError(t, sink.Close())
+
+	b, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "foo\nbar\n", string(b))
+	pipe.AssertExpectations()
+}
+
+func TestNewSink_Error(t *testing.T) {
+	_, err := file.NewSink("/does/not/exist/test.txt", 1)
+
+	assert.Error(t, err)
+}
+
+func TestSink_Process(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sink := file.NewWriterSink(buf, 2)
+
+	pipe := mocks.NewPipe(t)
+	pipe.ExpectMark(nil, "foo")
+	pipe.ExpectCommit()
+
+	sink.WithPipe(pipe)
+
+	assert.NoError(t, sink.Process(streams.NewMessage(nil, "foo")))
+	assert.NoError(t, sink.Process(streams.NewMessage(nil, []byte("bar"))))
+	assert.Equal(t, "", buf.String())
+
+	assert.NoError(t, sink.Commit(context.Background()))
+
+	assert.Equal(t, "foo\nbar\n", buf.String())
+	pipe.AssertExpectations()
+}
+
+func TestSink_ProcessWithEncoder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc := codec.EncoderFunc(func(v interface{}) ([]byte, error) {
+		return []byte("encoded"), nil
+	})
+	sink := file.NewWriterSink(buf, 0, file.WithValueEncoder(enc))
+
+	pipe := mocks.NewPipe(t)
+	pipe.ExpectMark(nil, 1)
+
+	sink.WithPipe(pipe)
+
+	assert.NoError(t, sink.Process(streams.NewMessage(nil, 1)))
+	assert.NoError(t, sink.Close())
+
+	assert.Equal(t, "encoded\n", buf.String())
+	pipe.AssertExpectations()
+}
+
+func TestSink_ProcessWithEncoderError(t *testing.T) {
+	enc := codec.EncoderFunc(func(interface{}) ([]byte, error) {
+		return nil, errors.New("test")
+	})
+	sink := file.NewWriterSink(&bytes.Buffer{}, 1, file.WithValueEncoder(enc))
+	sink.WithPipe(mocks.NewPipe(t))
+
+	err := sink.Process(streams.NewMessage(nil, "foo"))
+
+	assert.Error(t, err)
+}
+
+func TestSink_ProcessUnsupportedValue(t *testing.T) {
+	sink := file.NewWriterSink(&bytes.Buffer{}, 1)
+	sink.WithPipe(mocks.NewPipe(t))
+
+	err := sink.Process(streams.NewMessage(nil, 1))
+
+	assert.EqualError(t, err, "file: cannot write int")
+}
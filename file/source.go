@@ -0,0 +1,87 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/codec"
+)
+
+// SourceOptFunc represents a function that sets up the Source.
+type SourceOptFunc func(s *Source)
+
+// WithValueDecoder sets the decoder used on the Message value when it is consumed.
+func WithValueDecoder(dec codec.Decoder) SourceOptFunc {
+	return func(s *Source) {
+		s.valueDecoder = dec
+	}
+}
+
+// Source represents a source that consumes the lines of a file.
+//
+// Every line is consumed as a Message with a nil key and the line as
+// its value. Once all the lines are consumed, the Source returns io.EOF.
+type Source struct {
+	r *bufio.Reader
+	c io.Closer
+
+	valueDecoder codec.Decoder
+}
+
+// NewSource creates a new file Source, reading the file at the given path.
+func NewSource(path string, opts ...SourceOptFunc) (*Source, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := NewReaderSource(f, opts...)
+	s.c = f
+
+	return s, nil
+}
+
+// NewReaderSource creates a new file Source, reading from the given reader.
+func NewReaderSource(r io.Reader, opts ...SourceOptFunc) *Source {
+	s := &Source{r: bufio.NewReader(r)}
+
+	for _, optFn := range opts {
+		optFn(s)
+	}
+
+	return s
+}
+
+// Consume gets the next line from the Source.
+func (s *Source) Consume() (streams.Message, error) {
+	line, err := s.r.ReadBytes('\n')
+	if err != nil && (err != io.EOF || len(line) == 0) {
+		return streams.EmptyMessage, err
+	}
+
+	line = bytes.TrimRight(line, "\r\n")
+
+	msg, err := codec.DecodeMessage(streams.NewMessage(nil, line), nil, s.valueDecoder)
+	if err != nil {
+		return streams.EmptyMessage, err
+	}
+
+	return msg, nil
+}
+
+// Commit marks the consumed lines as processed.
+func (s *Source) Commit(interface{}) error {
+	return nil
+}
+
+// Close closes the Source.
+func (s *Source) Close() error {
+	if s.c == nil {
+		return nil
+	}
+
+	return s.c.Close()
+}
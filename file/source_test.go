@@ -0,0 +1,93 @@
+package file_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rafalmnich/streams/v6/codec"
+	"github.com/rafalmnich/streams/v6/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "streams")
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "test.txt")
+	if err := ioutil.WriteFile(path, []byte("foo\n"), 0644); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	src, err := file.NewSource(path)
+
+	assert.NoError(t, err)
+	msg, err := src.Consume()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("foo"), msg.Value)
+	assert.NoError(t, src.Close())
+}
+
+func TestNewSource_Error(t *testing.T) {
+	_, err := file.NewSource("/does/not/exist")
+
+	assert.Error(t, err)
+}
+
+func TestSource_Consume(t *testing.T) {
+	src := file.NewReaderSource(strings.NewReader("foo\r\nbar\nbaz"))
+
+	var values []interface{}
+	for {
+		msg, err := src.Consume()
+		if err == io.EOF {
+			break
+		}
+
+		assert.NoError(t, err)
+		assert.Nil(t, msg.Key)
+		values = append(values, msg.Value)
+	}
+
+	assert.Equal(t, []interface{}{[]byte("foo"), []byte("bar"), []byte("baz")}, values)
+}
+
+func TestSource_ConsumeWithDecoder(t *testing.T) {
+	src := file.NewReaderSource(strings.NewReader("foo\n"), file.WithValueDecoder(codec.StringDecoder{}))
+
+	msg, err := src.Consume()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", msg.Value)
+}
+
+func TestSource_ConsumeEOF(t *testing.T) {
+	src := file.NewReaderSource(strings.NewReader(""))
+
+	msg, err := src.Consume()
+
+	assert.Equal(t, io.EOF, err)
+	assert.True(t, msg.Empty())
+}
+
+func TestSource_Commit(t *testing.T) {
+	src := file.NewReaderSource(strings.NewReader(""))
+
+	err := src.Commit(nil)
+
+	assert.NoError(t, err)
+}
+
+func TestSource_Close(t *testing.T) {
+	src := file.NewReaderSource(strings.NewReader(""))
+
+	err := src.Close()
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,364 @@
+// Package golden provides golden file testing of stream topologies.
+//
+// A recorded input file of JSON lines is piped through a topology with the
+// streams.TopologyTestDriver, and the messages reaching the sinks, along
+// with the offsets committed to the sources, are compared to a golden file.
+// Running the tests with the GOLDEN_UPDATE environment variable set to true
+// regenerates the golden files:
+//
+//	GOLDEN_UPDATE=true go test ./...
+//
+// Every line of the input file is a record:
+//
+//	{"source": "src", "key": "1", "value": {"id": 1}, "headers": {"type": "user"}}
+//
+// The source may be omitted when the topology has a single source. String keys
+// and values are used as is, any other JSON is used in its raw form, before being
+// decoded with the configured decoders. The offset of a record is its position
+// among the records of its source, unless given with "offset".
+package golden
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/codec"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+)
+
+// UpdateEnv is the environment variable enabling the update of the golden files.
+const UpdateEnv = "GOLDEN_UPDATE"
+
+// OptFunc represents a function that sets up a golden file test.
+type OptFunc func(c *config)
+
+// WithKeyDecoder sets the decoder used on the recorded Message key.
+func WithKeyDecoder(dec codec.Decoder) OptFunc {
+	return func(c *config) {
+		c.keyDecoder = dec
+	}
+}
+
+// WithValueDecoder sets the decoder used on the recorded Message value.
+func WithValueDecoder(dec codec.Decoder) OptFunc {
+	return func(c *config) {
+		c.valueDecoder = dec
+	}
+}
+
+// WithKeyEncoder sets the encoder used on the Message key reaching a sink.
+func WithKeyEncoder(enc codec.Encoder) OptFunc {
+	return func(c *config) {
+		c.keyEncoder = enc
+	}
+}
+
+// WithValueEncoder sets the encoder used on the Message value reaching a sink.
+func WithValueEncoder(enc codec.Encoder) OptFunc {
+	return func(c *config) {
+		c.valueEncoder = enc
+	}
+}
+
+// WithUpdate sets whether the golden file is updated instead of compared,
+// overriding the GOLDEN_UPDATE environment variable.
+func WithUpdate(update bool) OptFunc {
+	return func(c *config) {
+		c.update = update
+	}
+}
+
+// WithDriverOpts sets the options of the test driver.
+func WithDriverOpts(opts ...streams.TestDriverOptFunc) OptFunc {
+	return func(c *config) {
+		c.driverOpts = append(c.driverOpts, opts...)
+	}
+}
+
+type config struct {
+	keyDecoder   codec.Decoder
+	valueDecoder codec.Decoder
+	keyEncoder   codec.Encoder
+	valueEncoder codec.Encoder
+	driverOpts   []streams.TestDriverOptFunc
+	update       bool
+}
+
+func newConfig(opts []OptFunc) *config {
+	c := &config{}
+	c.update, _ = strconv.ParseBool(os.Getenv(UpdateEnv))
+	for _, optFn := range opts {
+		optFn(c)
+	}
+
+	return c
+}
+
+// Run pipes the input file through the topology, comparing the
+// result to the golden file, or updating the golden file if the
+// GOLDEN_UPDATE environment variable is true or WithUpdate is given.
+func Run(t *testing.T, tp *streams.Topology, input, golden string, opts ...OptFunc) {
+	t.Helper()
+
+	f, err := os.Open(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	res, err := Replay(tp, f, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := res.MarshalIndent()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if newConfig(opts).update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("golden: %v (run with GOLDEN_UPDATE=true to create it)", err)
+	}
+
+	assert.Equal(t, string(want), string(got), "golden: %s does not match (run with GOLDEN_UPDATE=true to update it)", golden)
+}
+
+// Record represents a recorded Message.
+type Record struct {
+	Source  string            `json:"source,omitempty"`
+	Key     json.RawMessage   `json:"key,omitempty"`
+	Value   json.RawMessage   `json:"value,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Offset  *int64            `json:"offset,omitempty"`
+}
+
+// Result represents the result of piping the records through a topology.
+type Result struct {
+	// Outputs are the Messages reaching each sink.
+	Outputs map[string][]Output `json:"outputs"`
+	// Committed are the offsets committed to each source, in commit order.
+	Committed map[string][]int64 `json:"committed"`
+}
+
+// MarshalIndent returns the indented JSON encoding of the Result.
+func (r *Result) MarshalIndent() ([]byte, error) {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
+
+// Output represents a Message reaching a sink.
+type Output struct {
+	Key   interface{} `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// Replay pipes the records read from the reader through the topology.
+//
+// A sink is a processor node without children.
+func Replay(tp *streams.Topology, r io.Reader, opts ...OptFunc) (*Result, error) {
+	c := newConfig(opts)
+
+	sources := make([]string, 0, len(tp.Sources()))
+	for _, node := range tp.Sources() {
+		sources = append(sources, node.Name())
+	}
+	sort.Strings(sources)
+
+	d := streams.NewTopologyTestDriver(tp, c.driverOpts...)
+	offsets := map[string]int64{}
+
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 16*1024*1024)
+	for line := 1; s.Scan(); line++ {
+		if len(bytes.TrimSpace(s.Bytes())) == 0 {
+			continue
+		}
+
+		var rec Record
+		if err := json.Unmarshal(s.Bytes(), &rec); err != nil {
+			return nil, xerrors.Errorf("golden: line %d: %w", line, err)
+		}
+
+		if rec.Source == "" && len(sources) == 1 {
+			rec.Source = sources[0]
+		}
+
+		offset := offsets[rec.Source]
+		if rec.Offset != nil {
+			offset = *rec.Offset
+		}
+		offsets[rec.Source] = offset + 1
+
+		msg, err := c.message(rec, offset)
+		if err != nil {
+			return nil, xerrors.Errorf("golden: line %d: %w", line, err)
+		}
+
+		if err := d.PipeInput(rec.Source, msg); err != nil {
+			return nil, xerrors.Errorf("golden: line %d: %w", line, err)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := d.Close(); err != nil {
+		return nil, err
+	}
+
+	res := &Result{
+		Outputs:   map[string][]Output{},
+		Committed: map[string][]int64{},
+	}
+
+	for _, node := range tp.Processors() {
+		if len(node.Children()) > 0 {
+			continue
+		}
+
+		outputs := []Output{}
+		for _, msg := range d.ReadOutput(node.Name()) {
+			out, err := c.output(msg)
+			if err != nil {
+				return nil, xerrors.Errorf("golden: %s: %w", node.Name(), err)
+			}
+			outputs = append(outputs, out)
+		}
+		res.Outputs[node.Name()] = outputs
+	}
+
+	for _, name := range sources {
+		committed := []int64{}
+		for _, meta := range d.Committed(name) {
+			if o, ok := meta.(*Offset); ok {
+				committed = append(committed, o.Offset)
+			}
+		}
+		res.Committed[name] = committed
+	}
+
+	return res, nil
+}
+
+// message creates the Message of the record.
+func (c *config) message(rec Record, offset int64) (streams.Message, error) {
+	ctx := context.Background()
+	if len(rec.Headers) > 0 {
+		ctx = WithHeaders(ctx, rec.Headers)
+	}
+
+	msg := streams.NewMessageWithContext(ctx, raw(rec.Key), raw(rec.Value))
+	msg, err := codec.DecodeMessage(msg, c.keyDecoder, c.valueDecoder)
+	if err != nil {
+		return streams.EmptyMessage, err
+	}
+
+	return msg.WithMetadata(nil, &Offset{Offset: offset}), nil
+}
+
+// raw returns the raw bytes of the JSON, unquoting strings.
+func raw(v json.RawMessage) interface{} {
+	if len(v) == 0 || string(v) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(v, &s); err == nil {
+		return []byte(s)
+	}
+
+	return []byte(v)
+}
+
+// output creates the Output of the Message.
+func (c *config) output(msg streams.Message) (Output, error) {
+	msg, err := codec.EncodeMessage(msg, c.keyEncoder, c.valueEncoder)
+	if err != nil {
+		return Output{}, err
+	}
+
+	return Output{Key: printable(msg.Key), Value: printable(msg.Value)}, nil
+}
+
+// printable converts bytes to a string, so they are readable in the golden file.
+func printable(v interface{}) interface{} {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+
+	return v
+}
+
+// Offset is the Metadata of a recorded Message, its offset in its source.
+type Offset struct {
+	Origin streams.MetadataOrigin
+	Offset int64
+}
+
+// WithOrigin sets the MetadataOrigin on the metadata.
+func (o *Offset) WithOrigin(origin streams.MetadataOrigin) {
+	o.Origin = origin
+}
+
+// Merge merges the contained metadata into the given the metadata with the given strategy.
+func (o *Offset) Merge(v streams.Metadata, s streams.MetadataStrategy) streams.Metadata {
+	old, ok := v.(*Offset)
+	if !ok || old == nil {
+		return o
+	}
+
+	switch {
+	case o.Origin > old.Origin:
+		return old
+	case o.Origin < old.Origin:
+		return o
+	case s == streams.Lossless && o.Offset < old.Offset,
+		s == streams.Dupless && o.Offset > old.Offset:
+		return o
+	default:
+		return old
+	}
+}
+
+type headersKey struct{}
+
+// WithHeaders returns a copy of the context carrying the record headers.
+func WithHeaders(ctx context.Context, headers map[string]string) context.Context {
+	return context.WithValue(ctx, headersKey{}, headers)
+}
+
+// Headers returns the record headers carried by the context, if any.
+func Headers(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+
+	headers, _ := ctx.Value(headersKey{}).(map[string]string)
+	return headers
+}
@@ -0,0 +1,243 @@
+package golden_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/codec"
+	"github.com/rafalmnich/streams/v6/golden"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTopology() *streams.Topology {
+	b := streams.NewStreamBuilder()
+	b.Source("src", &fakeSource{}).
+		FilterFunc("filter", func(msg streams.Message) (bool, error) {
+			return msg.Value != "skip", nil
+		}).
+		MapFunc("lang", func(msg streams.Message) (streams.Message, error) {
+			if lang, ok := golden.Headers(msg.Ctx)["lang"]; ok {
+				msg.Value = lang + ":" + msg.Value.(string)
+			}
+			return msg, nil
+		}).
+		MapFunc("upper", func(msg streams.Message) (streams.Message, error) {
+			msg.Value = strings.ToUpper(msg.Value.(string))
+			return msg, nil
+		}).
+		Process("sink", &fakeCommitter{})
+
+	tp, _ := b.Build()
+
+	return tp
+}
+
+func TestRun(t *testing.T) {
+	golden.Run(t, newTopology(), "testdata/input.jsonl", "testdata/output.golden",
+		golden.WithValueDecoder(codec.StringDecoder{}),
+	)
+}
+
+func TestRun_Update(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "output.golden")
+
+	golden.Run(t, newTopology(), "testdata/input.jsonl", path,
+		golden.WithValueDecoder(codec.StringDecoder{}),
+		golden.WithUpdate(true),
+	)
+
+	got, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	want, err := ioutil.ReadFile("testdata/output.golden")
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestRun_UpdateEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "output.golden")
+
+	_ = os.Setenv(golden.UpdateEnv, "true")
+	defer os.Unsetenv(golden.UpdateEnv)
+
+	golden.Run(t, newTopology(), "testdata/input.jsonl", path,
+		golden.WithValueDecoder(codec.StringDecoder{}),
+	)
+
+	_, err = os.Stat(path)
+	assert.NoError(t, err)
+}
+
+func TestReplay(t *testing.T) {
+	input := `{"key": "1", "value": "a"}` + "\n\n" + `{"source": "src", "value": "skip"}`
+
+	res, err := golden.Replay(newTopology(), strings.NewReader(input), golden.WithValueDecoder(codec.StringDecoder{}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]golden.Output{"sink": {{Key: "1", Value: "A"}}}, res.Outputs)
+	assert.Equal(t, map[string][]int64{"src": {0}}, res.Committed)
+}
+
+func TestReplay_InvalidRecord(t *testing.T) {
+	_, err := golden.Replay(newTopology(), strings.NewReader("{"))
+
+	assert.Error(t, err)
+}
+
+func TestReplay_UnknownSource(t *testing.T) {
+	_, err := golden.Replay(newTopology(), strings.NewReader(`{"source": "nope", "value": "a"}`))
+
+	assert.Error(t, err)
+}
+
+func TestReplay_DecodeError(t *testing.T) {
+	dec := codec.DecoderFunc(func([]byte) (interface{}, error) {
+		return nil, assert.AnError
+	})
+
+	_, err := golden.Replay(newTopology(), strings.NewReader(`{"value": "a"}`), golden.WithKeyDecoder(dec))
+
+	assert.Error(t, err)
+}
+
+func TestResult_MarshalIndent(t *testing.T) {
+	res := &golden.Result{
+		Outputs:   map[string][]golden.Output{"sink": {{Key: nil, Value: "a"}}},
+		Committed: map[string][]int64{"src": {0}},
+	}
+
+	b, err := res.MarshalIndent()
+
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasSuffix(b, []byte("\n")))
+	assert.JSONEq(t, `{"outputs": {"sink": [{"key": null, "value": "a"}]}, "committed": {"src": [0]}}`, string(b))
+}
+
+func TestOffset_Merge(t *testing.T) {
+	tests := []struct {
+		name     string
+		new      *golden.Offset
+		old      streams.Metadata
+		strategy streams.MetadataStrategy
+		want     int64
+	}{
+		{
+			name:     "Nil",
+			new:      &golden.Offset{Offset: 1},
+			old:      nil,
+			strategy: streams.Lossless,
+			want:     1,
+		},
+		{
+			name:     "Lossless",
+			new:      &golden.Offset{Offset: 1},
+			old:      &golden.Offset{Offset: 2},
+			strategy: streams.Lossless,
+			want:     1,
+		},
+		{
+			name:     "Dupless",
+			new:      &golden.Offset{Offset: 1},
+			old:      &golden.Offset{Offset: 2},
+			strategy: streams.Dupless,
+			want:     2,
+		},
+		{
+			name:     "Committer Origin Wins",
+			new:      &golden.Offset{Origin: streams.ProcessorOrigin, Offset: 1},
+			old:      &golden.Offset{Origin: streams.CommitterOrigin, Offset: 2},
+			strategy: streams.Lossless,
+			want:     2,
+		},
+		{
+			name:     "New Committer Origin Wins",
+			new:      &golden.Offset{Origin: streams.CommitterOrigin, Offset: 3},
+			old:      &golden.Offset{Origin: streams.ProcessorOrigin, Offset: 2},
+			strategy: streams.Lossless,
+			want:     3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.new.Merge(tt.old, tt.strategy)
+
+			assert.Equal(t, tt.want, got.(*golden.Offset).Offset)
+		})
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	ctx := golden.WithHeaders(context.Background(), map[string]string{"a": "b"})
+
+	assert.Equal(t, map[string]string{"a": "b"}, golden.Headers(ctx))
+	assert.Nil(t, golden.Headers(context.Background()))
+}
+
+type fakeSource struct {
+	id int
+}
+
+func (*fakeSource) Consume() (streams.Message, error) {
+	return streams.EmptyMessage, nil
+}
+
+func (*fakeSource) Commit(interface{}) error {
+	return nil
+}
+
+func (*fakeSource) Close() error {
+	return nil
+}
+
+type fakeCommitter struct {
+	pipe streams.Pipe
+}
+
+func (p *fakeCommitter) WithPipe(pipe streams.Pipe) {
+	p.pipe = pipe
+}
+
+func (p *fakeCommitter) Process(msg streams.Message) error {
+	return p.pipe.Mark(msg)
+}
+
+func (p *fakeCommitter) Commit(ctx context.Context) error {
+	return nil
+}
+
+func (p *fakeCommitter) Close() error {
+	return nil
+}
+
+func TestRun_Mismatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "output.golden")
+	_ = ioutil.WriteFile(path, []byte("{}\n"), 0644)
+	mockT := new(testing.T)
+
+	golden.Run(mockT, newTopology(), "testdata/input.jsonl", path,
+		golden.WithValueDecoder(codec.StringDecoder{}),
+	)
+
+	assert.True(t, mockT.Failed())
+}
@@ -0,0 +1,115 @@
+package streams
+
+import (
+	"math"
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// histogramSubBits is the number of bits of precision below the leading bit of
+// a latency, bounding the relative error of the percentiles to 1/2^histogramSubBits.
+const histogramSubBits = 4
+
+// histogramBuckets is the number of buckets needed to cover every positive Duration.
+const histogramBuckets = (64 - histogramSubBits) << histogramSubBits
+
+// histogram represents a snapshot of a log-linear histogram of latencies.
+//
+// Latencies below 2^histogramSubBits nanoseconds are recorded exactly, larger
+// latencies are recorded in buckets of a width relative to the latency.
+type histogram struct {
+	counts [histogramBuckets]int64
+	total  int64
+	max    time.Duration
+}
+
+// quantile returns the latency at the quantile q, in the range [0, 1].
+//
+// The latency is the upper bound of its bucket, and is never more than the maximum.
+func (h *histogram) quantile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+
+	rank := int64(math.Ceil(q * float64(h.total)))
+	if rank < 1 {
+		rank = 1
+	}
+
+	var n int64
+	for i, c := range h.counts {
+		n += c
+		if n < rank {
+			continue
+		}
+
+		if l := histogramUpperBound(i); l < h.max {
+			return l
+		}
+		break
+	}
+
+	return h.max
+}
+
+// histogramBucket returns the index of the bucket of a latency.
+func histogramBucket(l time.Duration) int {
+	v := uint64(l)
+	if v < 1<<histogramSubBits {
+		return int(v)
+	}
+
+	shift := bits.Len64(v) - histogramSubBits - 1
+	return (shift+1)<<histogramSubBits | int(v>>uint(shift))&(1<<histogramSubBits-1)
+}
+
+// histogramUpperBound returns the largest latency recorded in the bucket.
+func histogramUpperBound(i int) time.Duration {
+	if i < 1<<histogramSubBits {
+		return time.Duration(i)
+	}
+
+	shift := uint(i>>histogramSubBits - 1)
+	mantissa := uint64(i&(1<<histogramSubBits-1)) + 1<<histogramSubBits
+	return time.Duration((mantissa+1)<<shift - 1)
+}
+
+// atomicHistogram represents a log-linear histogram of latencies that can be
+// recorded concurrently without locking.
+type atomicHistogram struct {
+	counts [histogramBuckets]int64
+	max    int64
+}
+
+// record records a latency in the histogram.
+func (h *atomicHistogram) record(l time.Duration) {
+	if l < 0 {
+		l = 0
+	}
+
+	atomic.AddInt64(&h.counts[histogramBucket(l)], 1)
+	for {
+		max := atomic.LoadInt64(&h.max)
+		if int64(l) <= max || atomic.CompareAndSwapInt64(&h.max, max, int64(l)) {
+			return
+		}
+	}
+}
+
+// snapshot adds the latencies recorded since the last snapshot to s, resetting the histogram.
+func (h *atomicHistogram) snapshot(s *histogram) {
+	for i := range h.counts {
+		if atomic.LoadInt64(&h.counts[i]) == 0 {
+			continue
+		}
+
+		n := atomic.SwapInt64(&h.counts[i], 0)
+		s.counts[i] += n
+		s.total += n
+	}
+
+	if max := time.Duration(atomic.SwapInt64(&h.max, 0)); max > s.max {
+		s.max = max
+	}
+}
@@ -0,0 +1,70 @@
+package streams
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram_Quantile(t *testing.T) {
+	var ah atomicHistogram
+	for i := 1; i <= 1000; i++ {
+		ah.record(time.Duration(i) * time.Millisecond)
+	}
+	var h histogram
+	ah.snapshot(&h)
+
+	assert.InEpsilon(t, 500*time.Millisecond, h.quantile(0.5), 1.0/16)
+	assert.InEpsilon(t, 950*time.Millisecond, h.quantile(0.95), 1.0/16)
+	assert.InEpsilon(t, 990*time.Millisecond, h.quantile(0.99), 1.0/16)
+	assert.Equal(t, time.Second, h.quantile(1))
+	assert.Equal(t, time.Second, h.max)
+}
+
+func TestHistogram_QuantileExactBelowSubBuckets(t *testing.T) {
+	var ah atomicHistogram
+	ah.record(3)
+	ah.record(7)
+	ah.record(-1)
+	var h histogram
+	ah.snapshot(&h)
+
+	assert.Equal(t, time.Duration(0), h.quantile(0))
+	assert.Equal(t, time.Duration(3), h.quantile(0.5))
+	assert.Equal(t, time.Duration(7), h.quantile(1))
+}
+
+func TestHistogram_QuantileEmpty(t *testing.T) {
+	var h histogram
+
+	assert.Equal(t, time.Duration(0), h.quantile(0.99))
+}
+
+func TestAtomicHistogram_SnapshotResets(t *testing.T) {
+	var ah atomicHistogram
+	ah.record(time.Second)
+
+	var first, second histogram
+	ah.snapshot(&first)
+	ah.snapshot(&second)
+
+	assert.Equal(t, int64(1), first.total)
+	assert.Equal(t, time.Second, first.max)
+	assert.Equal(t, int64(0), second.total)
+	assert.Equal(t, time.Duration(0), second.max)
+}
+
+func TestHistogramBucket(t *testing.T) {
+	tests := []time.Duration{0, 15, 16, 17, 31, 32, 1000, time.Second, time.Hour, 1<<63 - 1}
+
+	for _, l := range tests {
+		i := histogramBucket(l)
+
+		assert.True(t, i >= 0 && i < histogramBuckets, "bucket of %d out of range", l)
+		assert.True(t, l <= histogramUpperBound(i), "upper bound of %d", l)
+		if i > 0 {
+			assert.True(t, l > histogramUpperBound(i-1), "upper bound below %d", l)
+		}
+	}
+}
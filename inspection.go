@@ -1,9 +1,16 @@
 package streams
 
-import "errors"
+import (
+	"errors"
 
-// inspection represents an inspection that should be performed on the topology.
-type inspection func(map[Source]Node, []Node) error
+	"golang.org/x/xerrors"
+)
+
+// Inspection represents an inspection that should be performed on the topology.
+//
+// It is given the sources and processors of the topology, returning an
+// error if the topology is invalid.
+type Inspection func(map[Source]Node, []Node) error
 
 // sourcesConnected checks that all sources in a topology are connected.
 func sourcesConnected(srcs map[Source]Node, _ []Node) error {
@@ -59,3 +66,113 @@ func committerIsLeafNode(_ map[Source]Node, procs []Node) error {
 
 	return nil
 }
+
+// noCycles checks that the topology does not contain cycles.
+func noCycles(srcs map[Source]Node, procs []Node) error {
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	state := map[Node]int{}
+
+	var visit func(n Node) Node
+	visit = func(n Node) Node {
+		switch state[n] {
+		case visiting:
+			return n
+		case visited:
+			return nil
+		}
+
+		state[n] = visiting
+		for _, c := range n.Children() {
+			if cyclic := visit(c); cyclic != nil {
+				return cyclic
+			}
+		}
+		state[n] = visited
+
+		return nil
+	}
+
+	for _, node := range rootNodes(srcs, procs) {
+		if cyclic := visit(node); cyclic != nil {
+			return xerrors.Errorf("streams: topology contains a cycle at node %q", cyclic.Name())
+		}
+	}
+
+	return nil
+}
+
+// uniqueNames checks that no 2 nodes have the same name.
+func uniqueNames(srcs map[Source]Node, procs []Node) error {
+	names := map[string]bool{}
+	for _, node := range rootNodes(srcs, procs) {
+		if names[node.Name()] {
+			return xerrors.Errorf("streams: duplicate node name %q", node.Name())
+		}
+
+		names[node.Name()] = true
+	}
+
+	return nil
+}
+
+// branchesConnected checks that every output of a branch has a child.
+func branchesConnected(_ map[Source]Node, procs []Node) error {
+	for _, node := range procs {
+		branch, ok := node.Processor().(*BranchProcessor)
+		if !ok {
+			continue
+		}
+
+		if dangling := len(branch.preds) - len(node.Children()); dangling > 0 {
+			return xerrors.Errorf("streams: branch %q has %d outputs without children", node.Name(), dangling)
+		}
+	}
+
+	return nil
+}
+
+// processorsReachable checks that every processor is reachable from a source.
+func processorsReachable(srcs map[Source]Node, procs []Node) error {
+	reachable := map[Node]bool{}
+
+	var visit []Node
+	for _, node := range srcs {
+		visit = append(visit, node)
+	}
+
+	for len(visit) > 0 {
+		var n Node
+		n, visit = visit[0], visit[1:]
+
+		for _, c := range n.Children() {
+			if reachable[c] {
+				continue
+			}
+
+			reachable[c] = true
+			visit = append(visit, c)
+		}
+	}
+
+	for _, node := range procs {
+		if !reachable[node] {
+			return xerrors.Errorf("streams: processor %q is not reachable from a source", node.Name())
+		}
+	}
+
+	return nil
+}
+
+// rootNodes returns the source nodes, followed by the processor nodes.
+func rootNodes(srcs map[Source]Node, procs []Node) []Node {
+	nodes := make([]Node, 0, len(srcs)+len(procs))
+	for _, node := range srcs {
+		nodes = append(nodes, node)
+	}
+
+	return append(nodes, procs...)
+}
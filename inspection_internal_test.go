@@ -103,3 +103,98 @@ func TestCommitterIsLeafNode_Error(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestNoCycles(t *testing.T) {
+	node3 := &testNode{name: "3"}
+	node2 := &testNode{name: "2", children: []Node{node3}}
+	node1 := &testNode{name: "1", children: []Node{node2, node3}}
+
+	err := noCycles(map[Source]Node{
+		testSource(1): node1,
+	}, []Node{node2, node3})
+
+	assert.NoError(t, err)
+}
+
+func TestNoCycles_Error(t *testing.T) {
+	node3 := &testNode{name: "3"}
+	node2 := &testNode{name: "2", children: []Node{node3}}
+	node3.children = []Node{node2}
+	node1 := &testNode{name: "1", children: []Node{node2}}
+
+	err := noCycles(map[Source]Node{
+		testSource(1): node1,
+	}, []Node{node2, node3})
+
+	assert.EqualError(t, err, `streams: topology contains a cycle at node "2"`)
+}
+
+func TestUniqueNames(t *testing.T) {
+	node2 := &testNode{name: "2"}
+	node1 := &testNode{name: "1", children: []Node{node2}}
+
+	err := uniqueNames(map[Source]Node{
+		testSource(1): node1,
+	}, []Node{node2})
+
+	assert.NoError(t, err)
+}
+
+func TestUniqueNames_Error(t *testing.T) {
+	node2 := &testNode{name: "1"}
+	node1 := &testNode{name: "1", children: []Node{node2}}
+
+	err := uniqueNames(map[Source]Node{
+		testSource(1): node1,
+	}, []Node{node2})
+
+	assert.EqualError(t, err, `streams: duplicate node name "1"`)
+}
+
+func TestBranchesConnected(t *testing.T) {
+	branch := &testNode{
+		name:      "branch",
+		children:  []Node{&testNode{}, &testNode{}},
+		processor: NewBranchProcessor([]Predicate{PredicateFunc(nil), PredicateFunc(nil)}),
+	}
+
+	err := branchesConnected(map[Source]Node{}, []Node{branch})
+
+	assert.NoError(t, err)
+}
+
+func TestBranchesConnected_Error(t *testing.T) {
+	branch := &testNode{
+		name:      "branch",
+		children:  []Node{&testNode{}},
+		processor: NewBranchProcessor([]Predicate{PredicateFunc(nil), PredicateFunc(nil), PredicateFunc(nil)}),
+	}
+
+	err := branchesConnected(map[Source]Node{}, []Node{branch})
+
+	assert.EqualError(t, err, `streams: branch "branch" has 2 outputs without children`)
+}
+
+func TestProcessorsReachable(t *testing.T) {
+	node3 := &testNode{name: "3", processor: &testProcessor{}}
+	node2 := &testNode{name: "2", processor: &testProcessor{}, children: []Node{node3}}
+	node1 := &testNode{name: "1", children: []Node{node2}}
+
+	err := processorsReachable(map[Source]Node{
+		testSource(1): node1,
+	}, []Node{node2, node3})
+
+	assert.NoError(t, err)
+}
+
+func TestProcessorsReachable_Error(t *testing.T) {
+	node3 := &testNode{name: "3", processor: &testProcessor{}}
+	node2 := &testNode{name: "2", processor: &testProcessor{}}
+	node1 := &testNode{name: "1", children: []Node{node2}}
+
+	err := processorsReachable(map[Source]Node{
+		testSource(1): node1,
+	}, []Node{node2, node3})
+
+	assert.EqualError(t, err, `streams: processor "3" is not reachable from a source`)
+}
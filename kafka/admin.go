@@ -0,0 +1,141 @@
+package kafka
+
+import (
+	"github.com/Shopify/sarama"
+	"golang.org/x/xerrors"
+)
+
+// TopicConfig represents the topic settings ensured by the admin step of a Source or Sink.
+type TopicConfig struct {
+	// Create indicates that a missing topic should be created.
+	Create bool
+	// Partitions is the number of partitions of the topic. When set, the
+	// partition count of an existing topic is validated against it.
+	Partitions int32
+	// ReplicationFactor is the replication factor used when creating the topic.
+	ReplicationFactor int16
+	// CleanupPolicy is the cleanup policy used when creating the topic, e.g. "compact".
+	CleanupPolicy string
+	// CoPartitioned is a list of topics that must have the same number of
+	// partitions as the topic, e.g. the topics feeding the same join or merge.
+	CoPartitioned []string
+}
+
+// validate checks a TopicConfig instance. It will return a
+// sarama.ConfigurationError if the specified values don't make sense.
+func (c *TopicConfig) validate() error {
+	switch {
+	case c.Partitions < 0:
+		return sarama.ConfigurationError("Admin.Partitions must be at least 0")
+	case c.Create && c.Partitions == 0:
+		return sarama.ConfigurationError("Admin.Partitions must be at least 1 to create a topic")
+	case c.Create && c.ReplicationFactor <= 0:
+		return sarama.ConfigurationError("Admin.ReplicationFactor must be at least 1 to create a topic")
+	}
+
+	return nil
+}
+
+// setupTopic runs the admin step for the topic.
+func setupTopic(brokers []string, config *sarama.Config, topic string, c *TopicConfig) error {
+	admin, err := sarama.NewClusterAdmin(brokers, config)
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+
+	if err := ensureTopic(admin, topic, c); err != nil {
+		return err
+	}
+
+	if len(c.CoPartitioned) == 0 {
+		return nil
+	}
+
+	return ValidateCoPartitioning(admin, append([]string{topic}, c.CoPartitioned...)...)
+}
+
+// ensureTopic creates the topic if it is missing, or validates its partition count if it exists.
+func ensureTopic(admin sarama.ClusterAdmin, topic string, c *TopicConfig) error {
+	meta, err := admin.DescribeTopics([]string{topic})
+	if err != nil {
+		return err
+	}
+
+	if len(meta) == 0 || meta[0].Err == sarama.ErrUnknownTopicOrPartition {
+		if !c.Create {
+			return xerrors.Errorf("kafka: topic %q does not exist", topic)
+		}
+
+		return createTopic(admin, topic, c)
+	}
+
+	if meta[0].Err != sarama.ErrNoError {
+		return xerrors.Errorf("kafka: could not describe topic %q: %w", topic, meta[0].Err)
+	}
+
+	if c.Partitions > 0 && int32(len(meta[0].Partitions)) != c.Partitions {
+		return xerrors.Errorf("kafka: topic %q has %d partitions, expected %d", topic, len(meta[0].Partitions), c.Partitions)
+	}
+
+	return nil
+}
+
+func createTopic(admin sarama.ClusterAdmin, topic string, c *TopicConfig) error {
+	detail := &sarama.TopicDetail{
+		NumPartitions:     c.Partitions,
+		ReplicationFactor: c.ReplicationFactor,
+	}
+	if c.CleanupPolicy != "" {
+		policy := c.CleanupPolicy
+		detail.ConfigEntries = map[string]*string{"cleanup.policy": &policy}
+	}
+
+	err := admin.CreateTopic(topic, detail, false)
+	if terr, ok := err.(*sarama.TopicError); ok && terr.Err == sarama.ErrTopicAlreadyExists {
+		// The topic was created concurrently.
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("kafka: could not create topic %q: %w", topic, err)
+	}
+
+	return nil
+}
+
+// ValidateCoPartitioning checks that all the topics exist and have the same number of partitions.
+//
+// Topics feeding a join or merge of keyed data must be co-partitioned.
+func ValidateCoPartitioning(admin sarama.ClusterAdmin, topics ...string) error {
+	if len(topics) == 0 {
+		return nil
+	}
+
+	meta, err := admin.DescribeTopics(topics)
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[string]int, len(meta))
+	for _, tm := range meta {
+		if tm.Err != sarama.ErrNoError {
+			return xerrors.Errorf("kafka: could not describe topic %q: %w", tm.Name, tm.Err)
+		}
+
+		counts[tm.Name] = len(tm.Partitions)
+	}
+
+	first := topics[0]
+	for _, topic := range topics {
+		n, ok := counts[topic]
+		if !ok {
+			return xerrors.Errorf("kafka: topic %q does not exist", topic)
+		}
+
+		if n != counts[first] {
+			return xerrors.Errorf("kafka: topics are not co-partitioned: %q has %d partitions, %q has %d", first, counts[first], topic, n)
+		}
+	}
+
+	return nil
+}
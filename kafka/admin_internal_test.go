@@ -0,0 +1,173 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopicConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  TopicConfig
+		err  string
+	}{
+		{
+			name: "Valid",
+			cfg:  TopicConfig{Create: true, Partitions: 3, ReplicationFactor: 1},
+		},
+		{
+			name: "Negative Partitions",
+			cfg:  TopicConfig{Partitions: -1},
+			err:  "Admin.Partitions must be at least 0",
+		},
+		{
+			name: "Create Without Partitions",
+			cfg:  TopicConfig{Create: true, ReplicationFactor: 1},
+			err:  "Admin.Partitions must be at least 1 to create a topic",
+		},
+		{
+			name: "Create Without Replication",
+			cfg:  TopicConfig{Create: true, Partitions: 3},
+			err:  "Admin.ReplicationFactor must be at least 1 to create a topic",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validate()
+
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tt.err, string(err.(sarama.ConfigurationError)))
+		})
+	}
+}
+
+func TestEnsureTopic_CreatesMissingTopic(t *testing.T) {
+	admin := newFakeAdmin(map[string]int{})
+
+	err := ensureTopic(admin, "foo", &TopicConfig{Create: true, Partitions: 3, ReplicationFactor: 2, CleanupPolicy: "compact"})
+
+	assert.NoError(t, err)
+	if assert.Contains(t, admin.created, "foo") {
+		detail := admin.created["foo"]
+		assert.Equal(t, int32(3), detail.NumPartitions)
+		assert.Equal(t, int16(2), detail.ReplicationFactor)
+		assert.Equal(t, "compact", *detail.ConfigEntries["cleanup.policy"])
+	}
+}
+
+func TestEnsureTopic_IgnoresConcurrentlyCreatedTopic(t *testing.T) {
+	admin := newFakeAdmin(map[string]int{})
+	admin.createErr = &sarama.TopicError{Err: sarama.ErrTopicAlreadyExists}
+
+	err := ensureTopic(admin, "foo", &TopicConfig{Create: true, Partitions: 3, ReplicationFactor: 1})
+
+	assert.NoError(t, err)
+}
+
+func TestEnsureTopic_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		topics map[string]int
+		cfg    TopicConfig
+	}{
+		{
+			name:   "Missing Topic",
+			topics: map[string]int{},
+			cfg:    TopicConfig{},
+		},
+		{
+			name:   "Wrong Partition Count",
+			topics: map[string]int{"foo": 6},
+			cfg:    TopicConfig{Partitions: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin := newFakeAdmin(tt.topics)
+
+			err := ensureTopic(admin, "foo", &tt.cfg)
+
+			assert.Error(t, err)
+			assert.Empty(t, admin.created)
+		})
+	}
+}
+
+func TestEnsureTopic_ExistingTopic(t *testing.T) {
+	admin := newFakeAdmin(map[string]int{"foo": 3})
+
+	err := ensureTopic(admin, "foo", &TopicConfig{Create: true, Partitions: 3, ReplicationFactor: 1})
+
+	assert.NoError(t, err)
+	assert.Empty(t, admin.created)
+}
+
+func TestValidateCoPartitioning(t *testing.T) {
+	tests := []struct {
+		name    string
+		topics  []string
+		wantErr bool
+	}{
+		{name: "Co-Partitioned", topics: []string{"foo", "bar"}, wantErr: false},
+		{name: "Not Co-Partitioned", topics: []string{"foo", "baz"}, wantErr: true},
+		{name: "Missing Topic", topics: []string{"foo", "qux"}, wantErr: true},
+		{name: "No Topics", topics: []string{}, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin := newFakeAdmin(map[string]int{"foo": 3, "bar": 3, "baz": 6})
+
+			err := ValidateCoPartitioning(admin, tt.topics...)
+
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+type fakeAdmin struct {
+	sarama.ClusterAdmin
+
+	topics    map[string]int
+	created   map[string]*sarama.TopicDetail
+	createErr error
+}
+
+func newFakeAdmin(topics map[string]int) *fakeAdmin {
+	return &fakeAdmin{
+		topics:  topics,
+		created: map[string]*sarama.TopicDetail{},
+	}
+}
+
+func (a *fakeAdmin) DescribeTopics(topics []string) ([]*sarama.TopicMetadata, error) {
+	var meta []*sarama.TopicMetadata
+	for _, topic := range topics {
+		n, ok := a.topics[topic]
+		if !ok {
+			meta = append(meta, &sarama.TopicMetadata{Name: topic, Err: sarama.ErrUnknownTopicOrPartition})
+			continue
+		}
+
+		meta = append(meta, &sarama.TopicMetadata{Name: topic, Partitions: make([]*sarama.PartitionMetadata, n)})
+	}
+
+	return meta, nil
+}
+
+func (a *fakeAdmin) CreateTopic(topic string, detail *sarama.TopicDetail, _ bool) error {
+	if a.createErr != nil {
+		return a.createErr
+	}
+
+	a.created[topic] = detail
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/Shopify/sarama"
+)
+
+// Propagator propagates the values of a Message context, such as the
+// trace context, through the record headers.
+type Propagator interface {
+	// Extract returns a copy of the context with the values read from the headers of a consumed record.
+	Extract(ctx context.Context, headers []*sarama.RecordHeader) context.Context
+	// Inject writes the values of the context to the headers of a produced record.
+	Inject(ctx context.Context, msg *sarama.ProducerMessage)
+}
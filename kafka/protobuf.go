@@ -0,0 +1,189 @@
+package kafka
+
+import (
+	"encoding/binary"
+
+	"github.com/Shopify/sarama"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// confluentMagicByte is the first byte of data framed in the Confluent wire format.
+const confluentMagicByte = 0x0
+
+// HeadersDecoder represents a Kafka data decoder that is aware of the record headers.
+//
+// When a Source decoder implements this interface, DecodeWithHeaders
+// is used in favour of Decode.
+type HeadersDecoder interface {
+	Decoder
+
+	// DecodeWithHeaders transforms byte data to the desired type using the record headers.
+	DecodeWithHeaders([]byte, []*sarama.RecordHeader) (interface{}, error)
+}
+
+// ProtoDecoder represents a protobuf decoder.
+type ProtoDecoder struct {
+	// Prototype is the message the data is decoded into. A new instance is created for every record.
+	Prototype proto.Message
+	// Confluent indicates that the data is framed in the Confluent wire format.
+	Confluent bool
+}
+
+// Decode transforms byte data to a proto.Message.
+func (d ProtoDecoder) Decode(b []byte) (interface{}, error) {
+	if d.Prototype == nil {
+		return nil, xerrors.New("kafka: protobuf decoder requires a prototype")
+	}
+
+	return unmarshalProto(d.Prototype, b, d.Confluent)
+}
+
+var _ HeadersDecoder = ProtoRegistryDecoder{}
+
+// ProtoRegistryDecoder represents a protobuf decoder that resolves
+// the message type from a record header.
+type ProtoRegistryDecoder struct {
+	// Header is the name of the record header containing the message type.
+	Header string
+	// Types maps the message types to their prototypes.
+	Types map[string]proto.Message
+	// Confluent indicates that the data is framed in the Confluent wire format.
+	Confluent bool
+}
+
+// Decode always returns an error, as the message type cannot be resolved without headers.
+func (d ProtoRegistryDecoder) Decode([]byte) (interface{}, error) {
+	return nil, xerrors.New("kafka: protobuf registry decoder requires record headers")
+}
+
+// DecodeWithHeaders transforms byte data to a proto.Message of the type named in the record headers.
+func (d ProtoRegistryDecoder) DecodeWithHeaders(b []byte, headers []*sarama.RecordHeader) (interface{}, error) {
+	for _, h := range headers {
+		if h == nil || string(h.Key) != d.Header {
+			continue
+		}
+
+		prototype, ok := d.Types[string(h.Value)]
+		if !ok {
+			return nil, xerrors.Errorf("kafka: unknown protobuf type %q", string(h.Value))
+		}
+
+		return unmarshalProto(prototype, b, d.Confluent)
+	}
+
+	return nil, xerrors.Errorf("kafka: protobuf type header %q not found", d.Header)
+}
+
+// ProtoEncoder represents a protobuf encoder.
+type ProtoEncoder struct {
+	// Confluent indicates that the data should be framed in the Confluent wire format.
+	Confluent bool
+	// SchemaID is the schema registry ID written in the Confluent wire format.
+	SchemaID int32
+}
+
+// Encode transforms a proto.Message to bytes.
+func (e ProtoEncoder) Encode(v interface{}) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, xerrors.Errorf("kafka: cannot protobuf encode %T", v)
+	}
+
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if !e.Confluent {
+		return b, nil
+	}
+
+	prefix := make([]byte, 5, 5+len(b)+binary.MaxVarintLen64)
+	prefix[0] = confluentMagicByte
+	binary.BigEndian.PutUint32(prefix[1:], uint32(e.SchemaID))
+	prefix = appendMessageIndexes(prefix, messageIndexes(msg.ProtoReflect().Descriptor()))
+
+	return append(prefix, b...), nil
+}
+
+func unmarshalProto(prototype proto.Message, b []byte, confluent bool) (proto.Message, error) {
+	if confluent {
+		var err error
+		if b, err = stripConfluentHeader(b); err != nil {
+			return nil, err
+		}
+	}
+
+	msg := prototype.ProtoReflect().New().Interface()
+	if err := proto.Unmarshal(b, msg); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// stripConfluentHeader removes the magic byte, schema ID and message indexes from the data.
+func stripConfluentHeader(b []byte) ([]byte, error) {
+	if len(b) < 6 || b[0] != confluentMagicByte {
+		return nil, xerrors.New("kafka: invalid confluent wire format")
+	}
+	b = b[5:]
+
+	count, n := binary.Varint(b)
+	if n <= 0 || count < 0 {
+		return nil, xerrors.New("kafka: invalid confluent message indexes")
+	}
+	b = b[n:]
+
+	for i := int64(0); i < count; i++ {
+		if _, n = binary.Varint(b); n <= 0 {
+			return nil, xerrors.New("kafka: invalid confluent message indexes")
+		}
+		b = b[n:]
+	}
+
+	return b, nil
+}
+
+// messageIndexes returns the path of the message descriptor within its file.
+func messageIndexes(desc protoreflect.MessageDescriptor) []int {
+	var idxs []int
+	var d protoreflect.Descriptor = desc
+	for {
+		md, ok := d.(protoreflect.MessageDescriptor)
+		if !ok {
+			break
+		}
+
+		idxs = append([]int{md.Index()}, idxs...)
+		d = md.Parent()
+	}
+
+	return idxs
+}
+
+// appendMessageIndexes appends the message indexes to the buffer.
+//
+// The common case of the first message in the file is written as a single zero byte.
+func appendMessageIndexes(b []byte, idxs []int) []byte {
+	var buf [binary.MaxVarintLen64]byte
+
+	if len(idxs) == 1 && idxs[0] == 0 {
+		return append(b, 0)
+	}
+
+	n := binary.PutVarint(buf[:], int64(len(idxs)))
+	b = append(b, buf[:n]...)
+	for _, idx := range idxs {
+		n = binary.PutVarint(buf[:], int64(idx))
+		b = append(b, buf[:n]...)
+	}
+
+	return b
+}
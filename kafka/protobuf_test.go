@@ -0,0 +1,208 @@
+package kafka_test
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/rafalmnich/streams/v6/kafka"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestProtoDecoder_Decode(t *testing.T) {
+	in, _ := proto.Marshal(wrapperspb.String("foobar"))
+	dec := kafka.ProtoDecoder{Prototype: &wrapperspb.StringValue{}}
+
+	got, err := dec.Decode(in)
+
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(wrapperspb.String("foobar"), got.(proto.Message)))
+}
+
+func TestProtoDecoder_DecodeConfluent(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []byte
+	}{
+		{
+			name: "Single Zero Index",
+			in:   []byte{0, 0, 0, 0, 1, 0},
+		},
+		{
+			name: "Index Array",
+			in:   []byte{0, 0, 0, 0, 1, 2, 14},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _ := proto.Marshal(wrapperspb.String("foobar"))
+			dec := kafka.ProtoDecoder{Prototype: &wrapperspb.StringValue{}, Confluent: true}
+
+			got, err := dec.Decode(append(tt.in, b...))
+
+			assert.NoError(t, err)
+			assert.True(t, proto.Equal(wrapperspb.String("foobar"), got.(proto.Message)))
+		})
+	}
+}
+
+func TestProtoDecoder_DecodeError(t *testing.T) {
+	tests := []struct {
+		name string
+		dec  kafka.ProtoDecoder
+		in   []byte
+	}{
+		{
+			name: "No Prototype",
+			dec:  kafka.ProtoDecoder{},
+			in:   []byte{},
+		},
+		{
+			name: "Invalid Data",
+			dec:  kafka.ProtoDecoder{Prototype: &wrapperspb.StringValue{}},
+			in:   []byte{0xff},
+		},
+		{
+			name: "Invalid Magic Byte",
+			dec:  kafka.ProtoDecoder{Prototype: &wrapperspb.StringValue{}, Confluent: true},
+			in:   []byte{1, 0, 0, 0, 1, 0},
+		},
+		{
+			name: "Truncated Header",
+			dec:  kafka.ProtoDecoder{Prototype: &wrapperspb.StringValue{}, Confluent: true},
+			in:   []byte{0, 0, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.dec.Decode(tt.in)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestProtoRegistryDecoder_Decode(t *testing.T) {
+	dec := kafka.ProtoRegistryDecoder{}
+
+	_, err := dec.Decode([]byte{})
+
+	assert.Error(t, err)
+}
+
+func TestProtoRegistryDecoder_DecodeWithHeaders(t *testing.T) {
+	in, _ := proto.Marshal(wrapperspb.Int64(42))
+	dec := kafka.ProtoRegistryDecoder{
+		Header: "type",
+		Types: map[string]proto.Message{
+			"string": &wrapperspb.StringValue{},
+			"int64":  &wrapperspb.Int64Value{},
+		},
+	}
+
+	got, err := dec.DecodeWithHeaders(in, []*sarama.RecordHeader{
+		{Key: []byte("other"), Value: []byte("string")},
+		{Key: []byte("type"), Value: []byte("int64")},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(wrapperspb.Int64(42), got.(proto.Message)))
+}
+
+func TestProtoRegistryDecoder_DecodeWithHeadersError(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []*sarama.RecordHeader
+	}{
+		{
+			name:    "Missing Header",
+			headers: []*sarama.RecordHeader{},
+		},
+		{
+			name:    "Unknown Type",
+			headers: []*sarama.RecordHeader{{Key: []byte("type"), Value: []byte("bool")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := kafka.ProtoRegistryDecoder{
+				Header: "type",
+				Types:  map[string]proto.Message{"string": &wrapperspb.StringValue{}},
+			}
+
+			_, err := dec.DecodeWithHeaders([]byte{}, tt.headers)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestProtoEncoder_Encode(t *testing.T) {
+	b, _ := proto.Marshal(wrapperspb.String("foobar"))
+
+	tests := []struct {
+		name string
+		enc  kafka.ProtoEncoder
+		in   interface{}
+		want []byte
+	}{
+		{
+			name: "Plain",
+			enc:  kafka.ProtoEncoder{},
+			in:   wrapperspb.String("foobar"),
+			want: b,
+		},
+		{
+			name: "Confluent",
+			enc:  kafka.ProtoEncoder{Confluent: true, SchemaID: 258},
+			in:   wrapperspb.String("foobar"),
+			want: append([]byte{0, 0, 0, 1, 2, 2, 14}, b...),
+		},
+		{
+			name: "Confluent First Message",
+			enc:  kafka.ProtoEncoder{Confluent: true, SchemaID: 1},
+			in:   wrapperspb.Double(0),
+			want: []byte{0, 0, 0, 0, 1, 0},
+		},
+		{
+			name: "Nil",
+			enc:  kafka.ProtoEncoder{},
+			in:   nil,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.enc.Encode(tt.in)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProtoEncoder_EncodeError(t *testing.T) {
+	enc := kafka.ProtoEncoder{}
+
+	_, err := enc.Encode("foobar")
+
+	assert.Error(t, err)
+}
+
+func TestProtoEncoder_RoundTrip(t *testing.T) {
+	enc := kafka.ProtoEncoder{Confluent: true, SchemaID: 7}
+	dec := kafka.ProtoDecoder{Prototype: &wrapperspb.BytesValue{}, Confluent: true}
+
+	b, err := enc.Encode(wrapperspb.Bytes([]byte("foobar")))
+	assert.NoError(t, err)
+
+	got, err := dec.Decode(b)
+
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(wrapperspb.Bytes([]byte("foobar")), got.(proto.Message)))
+}
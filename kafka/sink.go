@@ -18,6 +18,14 @@ type SinkConfig struct {
 	ValueEncoder Encoder
 
 	BatchSize int
+
+	// Propagator enables injecting the trace context of the Message context
+	// into the record headers. Record headers require Kafka v0.11 or later,
+	// so Version must be set to at least sarama.V0_11_0_0.
+	Propagator Propagator
+
+	// Admin enables the admin step, creating or validating the topic when the Sink is created.
+	Admin *TopicConfig
 }
 
 // NewSinkConfig creates a new SinkConfig.
@@ -51,6 +59,10 @@ func (c *SinkConfig) Validate() error {
 		return sarama.ConfigurationError("ValueEncoder must be an instance of Encoder")
 	case c.BatchSize <= 0:
 		return sarama.ConfigurationError("BatchSize must be at least 1")
+	case c.Propagator != nil && !c.Version.IsAtLeast(sarama.V0_11_0_0):
+		return sarama.ConfigurationError("Version must be at least 0.11.0 to use a Propagator")
+	case c.Admin != nil:
+		return c.Admin.validate()
 	}
 
 	return nil
@@ -62,6 +74,7 @@ type Sink struct {
 
 	keyEncoder   Encoder
 	valueEncoder Encoder
+	propagator   Propagator
 
 	topic    string
 	producer sarama.SyncProducer
@@ -77,6 +90,12 @@ func NewSink(c *SinkConfig) (*Sink, error) {
 		return nil, err
 	}
 
+	if c.Admin != nil {
+		if err := setupTopic(c.Brokers, &c.Config, c.Topic, c.Admin); err != nil {
+			return nil, err
+		}
+	}
+
 	p, err := sarama.NewSyncProducer(c.Brokers, &c.Config)
 	if err != nil {
 		return nil, err
@@ -86,6 +105,7 @@ func NewSink(c *SinkConfig) (*Sink, error) {
 		topic:        c.Topic,
 		keyEncoder:   c.KeyEncoder,
 		valueEncoder: c.ValueEncoder,
+		propagator:   c.Propagator,
 		producer:     p,
 		batch:        c.BatchSize,
 		buf:          make([]*sarama.ProducerMessage, 0, c.BatchSize),
@@ -106,20 +126,29 @@ func (p *Sink) Process(msg streams.Message) error {
 		return err
 	}
 
-	v, err := p.valueEncoder.Encode(msg.Value)
-	if err != nil {
-		return err
-	}
-
 	var keyEnc sarama.Encoder
 	if k != nil {
 		keyEnc = sarama.ByteEncoder(k)
 	}
 
+	// A nil value is produced as a null record value (tombstone).
+	var valEnc sarama.Encoder
+	if msg.Value != nil {
+		v, err := p.valueEncoder.Encode(msg.Value)
+		if err != nil {
+			return err
+		}
+
+		valEnc = sarama.ByteEncoder(v)
+	}
+
 	pm := &sarama.ProducerMessage{
 		Topic: p.topic,
 		Key:   keyEnc,
-		Value: sarama.ByteEncoder(v),
+		Value: valEnc,
+	}
+	if p.propagator != nil && msg.Ctx != nil {
+		p.propagator.Inject(msg.Ctx, pm)
 	}
 	p.buf = append(p.buf, pm)
 	p.count++
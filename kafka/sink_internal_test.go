@@ -1,11 +1,13 @@
 package kafka
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	"github.com/Shopify/sarama"
 	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/mocks"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -32,8 +34,70 @@ func TestSink_ConsumeReturnsValueEncodeError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestSink_ProcessProducesTombstones(t *testing.T) {
+	pipe := mocks.NewPipe(t)
+	pipe.ExpectMark("foo", nil)
+	s := Sink{
+		pipe:         pipe,
+		keyEncoder:   StringEncoder{},
+		valueEncoder: errorEncoder{},
+		batch:        10,
+		buf:          []*sarama.ProducerMessage{},
+	}
+
+	err := s.Process(streams.NewMessage("foo", nil))
+
+	assert.NoError(t, err)
+	assert.Len(t, s.buf, 1)
+	assert.Equal(t, sarama.ByteEncoder("foo"), s.buf[0].Key)
+	assert.Nil(t, s.buf[0].Value)
+	pipe.AssertExpectations()
+}
+
 type errorEncoder struct{}
 
 func (errorEncoder) Encode(interface{}) ([]byte, error) {
 	return nil, errors.New("test")
 }
+
+func TestSink_ProcessInjectsContext(t *testing.T) {
+	pipe := mocks.NewPipe(t)
+	pipe.ExpectMark("foo", "bar")
+	ctx := context.WithValue(context.Background(), traceKey{}, "trace")
+	s := Sink{
+		pipe:         pipe,
+		keyEncoder:   StringEncoder{},
+		valueEncoder: StringEncoder{},
+		propagator:   fakePropagator{},
+		batch:        10,
+		buf:          []*sarama.ProducerMessage{},
+	}
+
+	err := s.Process(streams.NewMessageWithContext(ctx, "foo", "bar"))
+
+	assert.NoError(t, err)
+	assert.Len(t, s.buf, 1)
+	assert.Equal(t, []sarama.RecordHeader{{Key: []byte("trace"), Value: []byte("trace")}}, s.buf[0].Headers)
+	pipe.AssertExpectations()
+}
+
+type traceKey struct{}
+
+// fakePropagator propagates the trace context value through the "trace" header.
+type fakePropagator struct{}
+
+func (fakePropagator) Extract(ctx context.Context, headers []*sarama.RecordHeader) context.Context {
+	for _, h := range headers {
+		if string(h.Key) == "trace" {
+			return context.WithValue(ctx, traceKey{}, string(h.Value))
+		}
+	}
+
+	return ctx
+}
+
+func (fakePropagator) Inject(ctx context.Context, msg *sarama.ProducerMessage) {
+	if v, ok := ctx.Value(traceKey{}).(string); ok {
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte("trace"), Value: []byte(v)})
+	}
+}
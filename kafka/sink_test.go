@@ -26,6 +26,17 @@ func TestSinkConfig_Validate(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestSinkConfig_ValidateWithPropagator(t *testing.T) {
+	c := kafka.NewSinkConfig()
+	c.Brokers = []string{"test"}
+	c.Version = sarama.V0_11_0_0
+	c.Propagator = nopPropagator{}
+
+	err := c.Validate()
+
+	assert.NoError(t, err)
+}
+
 func TestSinkConfig_ValidateErrors(t *testing.T) {
 	tests := []struct {
 		name string
@@ -63,6 +74,22 @@ func TestSinkConfig_ValidateErrors(t *testing.T) {
 			},
 			err: "BatchSize must be at least 1",
 		},
+		{
+			name: "PropagatorVersion",
+			cfg: func(c *kafka.SinkConfig) {
+				c.Brokers = []string{"test"}
+				c.Propagator = nopPropagator{}
+			},
+			err: "Version must be at least 0.11.0 to use a Propagator",
+		},
+		{
+			name: "Admin",
+			cfg: func(c *kafka.SinkConfig) {
+				c.Brokers = []string{"test"}
+				c.Admin = &kafka.TopicConfig{Create: true}
+			},
+			err: "Admin.Partitions must be at least 1 to create a topic",
+		},
 		{
 			name: "BaseConfig",
 			cfg: func(c *kafka.SinkConfig) {
@@ -237,3 +264,11 @@ func TestSink_CommitError(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+type nopPropagator struct{}
+
+func (nopPropagator) Extract(ctx context.Context, _ []*sarama.RecordHeader) context.Context {
+	return ctx
+}
+
+func (nopPropagator) Inject(context.Context, *sarama.ProducerMessage) {}
@@ -24,6 +24,17 @@ type SourceConfig struct {
 
 	BufferSize       int
 	ErrorsBufferSize int
+
+	// Admin enables the admin step, creating or validating the topic when the Source is created.
+	Admin *TopicConfig
+
+	// Tombstones surfaces records with a null value as Messages with a nil Value,
+	// instead of passing the empty data to the ValueDecoder.
+	Tombstones bool
+
+	// Propagator enables extracting the trace context from the record headers
+	// into the Message context.
+	Propagator Propagator
 }
 
 // NewSourceConfig creates a new Kafka source configuration.
@@ -57,6 +68,8 @@ func (c *SourceConfig) Validate() error {
 		return sarama.ConfigurationError("ValueDecoder must be an instance of Decoder")
 	case c.BufferSize <= 0:
 		return sarama.ConfigurationError("BufferSize must be at least 1")
+	case c.Admin != nil:
+		return c.Admin.validate()
 	}
 
 	return nil
@@ -131,10 +144,15 @@ type Source struct {
 	ctx          context.Context
 	keyDecoder   Decoder
 	valueDecoder Decoder
+	tombstones   bool
+	propagator   Propagator
 
-	buf     chan *sarama.ConsumerMessage
-	errs    chan error
-	lastErr error
+	buf      chan *sarama.ConsumerMessage
+	errs     chan error
+	errMu    sync.Mutex
+	lastErr  error
+	failed   chan struct{}
+	failOnce sync.Once
 
 	session   sarama.ConsumerGroupSession
 	cancelCtx func()
@@ -151,6 +169,12 @@ func NewSource(c *SourceConfig) (*Source, error) {
 		return nil, err
 	}
 
+	if c.Admin != nil {
+		if err := setupTopic(c.Brokers, &c.Config, c.Topic, c.Admin); err != nil {
+			return nil, err
+		}
+	}
+
 	consumer, err := sarama.NewConsumerGroup(c.Brokers, c.GroupID, &c.Config)
 	if err != nil {
 		return nil, err
@@ -164,8 +188,11 @@ func NewSource(c *SourceConfig) (*Source, error) {
 		ctx:          ctx,
 		keyDecoder:   c.KeyDecoder,
 		valueDecoder: c.ValueDecoder,
+		tombstones:   c.Tombstones,
+		propagator:   c.Propagator,
 		buf:          make(chan *sarama.ConsumerMessage, c.BufferSize),
 		errs:         make(chan error, c.ErrorsBufferSize),
+		failed:       make(chan struct{}),
 		cancelCtx:    cancel,
 		done:         make(chan struct{}),
 	}
@@ -179,29 +206,63 @@ func NewSource(c *SourceConfig) (*Source, error) {
 
 // Consume gets the next record from the Source.
 func (s *Source) Consume() (streams.Message, error) {
-	if s.lastErr != nil {
-		return streams.EmptyMessage, s.lastErr
+	if err := s.err(); err != nil {
+		return streams.EmptyMessage, err
 	}
 
 	select {
 	case msg := <-s.buf:
-		k, err := s.keyDecoder.Decode(msg.Key)
-		if err != nil {
-			return streams.EmptyMessage, err
-		}
+		return s.message(msg)
+
+	case <-time.After(100 * time.Millisecond):
+		return streams.EmptyMessage, nil
+	}
+}
+
+// ConsumeContext gets the next record from the Source, blocking until
+// a record is available, the consumer fails or the context is done.
+func (s *Source) ConsumeContext(ctx context.Context) (streams.Message, error) {
+	select {
+	case <-s.failed:
+		return streams.EmptyMessage, s.err()
+	default:
+	}
+
+	select {
+	case msg := <-s.buf:
+		return s.message(msg)
+
+	case <-s.failed:
+		return streams.EmptyMessage, s.err()
 
-		v, err := s.valueDecoder.Decode(msg.Value)
+	case <-ctx.Done():
+		return streams.EmptyMessage, ctx.Err()
+	}
+}
+
+// message decodes the consumer message into a Message.
+func (s *Source) message(msg *sarama.ConsumerMessage) (streams.Message, error) {
+	k, err := decode(s.keyDecoder, msg.Key, msg.Headers)
+	if err != nil {
+		return streams.EmptyMessage, err
+	}
+
+	var v interface{}
+	if !s.tombstones || msg.Value != nil {
+		v, err = decode(s.valueDecoder, msg.Value, msg.Headers)
 		if err != nil {
 			return streams.EmptyMessage, err
 		}
+	}
 
-		m := streams.NewMessageWithContext(s.ctx, k, v).
-			WithMetadata(s, s.createMetadata(msg))
-		return m, nil
-
-	case <-time.After(100 * time.Millisecond):
-		return streams.EmptyMessage, nil
+	ctx := s.ctx
+	if s.propagator != nil {
+		ctx = s.propagator.Extract(ctx, msg.Headers)
 	}
+
+	m := streams.NewMessageWithContext(ctx, k, v).
+		WithMetadata(s, s.createMetadata(msg))
+	return m, nil
 }
 
 // Commit marks the consumed records as processed.
@@ -280,6 +341,15 @@ func (s *Source) ConsumeClaim(_ sarama.ConsumerGroupSession, claim sarama.Consum
 	return nil
 }
 
+// decode decodes the data, passing the record headers to decoders that accept them.
+func decode(dec Decoder, b []byte, headers []*sarama.RecordHeader) (interface{}, error) {
+	if hdec, ok := dec.(HeadersDecoder); ok {
+		return hdec.DecodeWithHeaders(b, headers)
+	}
+
+	return dec.Decode(b)
+}
+
 func (s *Source) createMetadata(msg *sarama.ConsumerMessage) Metadata {
 	return Metadata{&PartitionOffset{
 		Topic:     msg.Topic,
@@ -294,13 +364,30 @@ func (s *Source) readErrors() {
 		case <-s.done:
 			return
 		case err := <-s.errs:
-			s.lastErr = err
+			s.fail(err)
 		case err := <-s.consumer.Errors():
-			s.lastErr = err
+			s.fail(err)
 		}
 	}
 }
 
+// fail records the consumer error and wakes up blocked consumers.
+func (s *Source) fail(err error) {
+	s.errMu.Lock()
+	s.lastErr = err
+	s.errMu.Unlock()
+
+	s.failOnce.Do(func() { close(s.failed) })
+}
+
+// err returns the last consumer error.
+func (s *Source) err() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+
+	return s.lastErr
+}
+
 func (s *Source) runConsumerGroup(ctx context.Context, topic string) {
 	s.consumerWG.Add(1)
 	defer s.consumerWG.Done()
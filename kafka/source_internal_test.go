@@ -1,6 +1,7 @@
 package kafka
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -52,6 +53,89 @@ func TestSource_ConsumeReturnsValueDecodeError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestSource_ConsumeSurfacesTombstones(t *testing.T) {
+	s := Source{
+		keyDecoder:   StringDecoder{},
+		valueDecoder: errorDecoder{},
+		tombstones:   true,
+		buf:          make(chan *sarama.ConsumerMessage, 1),
+	}
+
+	s.buf <- &sarama.ConsumerMessage{
+		Key:   []byte("foo"),
+		Value: []byte(nil),
+	}
+
+	msg, err := s.Consume()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", msg.Key)
+	assert.Nil(t, msg.Value)
+}
+
+func TestSource_ConsumeDecodesNullValuesWithoutTombstones(t *testing.T) {
+	s := Source{
+		keyDecoder:   StringDecoder{},
+		valueDecoder: StringDecoder{},
+		buf:          make(chan *sarama.ConsumerMessage, 1),
+	}
+
+	s.buf <- &sarama.ConsumerMessage{
+		Key:   []byte("foo"),
+		Value: []byte(nil),
+	}
+
+	msg, err := s.Consume()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", msg.Value)
+}
+
+func TestSource_ConsumeContext(t *testing.T) {
+	s := Source{
+		keyDecoder:   StringDecoder{},
+		valueDecoder: StringDecoder{},
+		buf:          make(chan *sarama.ConsumerMessage, 1),
+	}
+
+	s.buf <- &sarama.ConsumerMessage{
+		Key:   []byte("foo"),
+		Value: []byte("bar"),
+	}
+
+	msg, err := s.ConsumeContext(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", msg.Key)
+	assert.Equal(t, "bar", msg.Value)
+}
+
+func TestSource_ConsumeContextReturnsConsumerError(t *testing.T) {
+	want := errors.New("test")
+	s := Source{
+		buf:    make(chan *sarama.ConsumerMessage, 1),
+		failed: make(chan struct{}),
+	}
+
+	go s.fail(want)
+	_, err := s.ConsumeContext(context.Background())
+
+	assert.Equal(t, want, err)
+}
+
+func TestSource_ConsumeContextCancelled(t *testing.T) {
+	s := Source{
+		buf: make(chan *sarama.ConsumerMessage, 1),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	msg, err := s.ConsumeContext(ctx)
+
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, msg.Empty())
+}
+
 func TestSource_ConsumeTimesOut(t *testing.T) {
 	s := Source{
 		buf: make(chan *sarama.ConsumerMessage, 1),
@@ -64,8 +148,87 @@ func TestSource_ConsumeTimesOut(t *testing.T) {
 	assert.Equal(t, nil, msg.Value)
 }
 
+func TestSource_ConsumePassesHeadersToDecoder(t *testing.T) {
+	headers := []*sarama.RecordHeader{{Key: []byte("type"), Value: []byte("foo")}}
+	dec := &headersDecoder{}
+	s := Source{
+		keyDecoder:   ByteDecoder{},
+		valueDecoder: dec,
+		buf:          make(chan *sarama.ConsumerMessage, 1),
+	}
+
+	s.buf <- &sarama.ConsumerMessage{
+		Key:     []byte(nil),
+		Value:   []byte("foo"),
+		Headers: headers,
+	}
+
+	msg, err := s.Consume()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", msg.Value)
+	assert.Equal(t, headers, dec.headers)
+}
+
+type headersDecoder struct {
+	headers []*sarama.RecordHeader
+}
+
+func (*headersDecoder) Decode([]byte) (interface{}, error) {
+	return nil, errors.New("test")
+}
+
+func (d *headersDecoder) DecodeWithHeaders(b []byte, headers []*sarama.RecordHeader) (interface{}, error) {
+	d.headers = headers
+
+	return string(b), nil
+}
+
 type errorDecoder struct{}
 
 func (errorDecoder) Decode([]byte) (interface{}, error) {
 	return nil, errors.New("test")
 }
+
+func TestSource_ConsumeExtractsContext(t *testing.T) {
+	s := Source{
+		ctx:          context.Background(),
+		keyDecoder:   ByteDecoder{},
+		valueDecoder: ByteDecoder{},
+		propagator:   fakePropagator{},
+		buf:          make(chan *sarama.ConsumerMessage, 1),
+	}
+
+	s.buf <- &sarama.ConsumerMessage{
+		Key:     []byte(nil),
+		Value:   []byte("foo"),
+		Headers: []*sarama.RecordHeader{{Key: []byte("trace"), Value: []byte("trace")}},
+	}
+
+	msg, err := s.Consume()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "trace", msg.Ctx.Value(traceKey{}))
+}
+
+func TestSource_FailConcurrentlyWithConsume(t *testing.T) {
+	s := Source{
+		buf:    make(chan *sarama.ConsumerMessage),
+		failed: make(chan struct{}),
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		s.fail(errors.New("first"))
+		s.fail(errors.New("second"))
+	}()
+
+	_, err := s.ConsumeContext(context.Background())
+	<-done
+
+	assert.Error(t, err)
+	_, err = s.Consume()
+	assert.EqualError(t, err, "second")
+}
@@ -62,6 +62,14 @@ func TestSourceConfig_ValidateErrors(t *testing.T) {
 			},
 			err: "BufferSize must be at least 1",
 		},
+		{
+			name: "Admin",
+			cfg: func(c *kafka.SourceConfig) {
+				c.Brokers = []string{"test"}
+				c.Admin = &kafka.TopicConfig{Create: true}
+			},
+			err: "Admin.Partitions must be at least 1 to create a topic",
+		},
 		{
 			name: "BaseConfig",
 			cfg: func(c *kafka.SourceConfig) {
@@ -0,0 +1,171 @@
+package mocks
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/rafalmnich/streams/v6"
+)
+
+var _ = (streams.Metastore)(&Metastore{})
+
+type markRecord struct {
+	proc interface{}
+	src  interface{}
+	meta interface{}
+}
+
+type pullRecord struct {
+	proc  interface{}
+	items streams.Metaitems
+}
+
+// Metastore is a mock Metastore.
+type Metastore struct {
+	t *testing.T
+
+	mu sync.Mutex
+
+	shouldError bool
+
+	expectMark    []markRecord
+	expectPull    []pullRecord
+	expectPullAll []map[streams.Processor]streams.Metaitems
+}
+
+// NewMetastore creates a new mock Metastore instance.
+func NewMetastore(t *testing.T) *Metastore {
+	return &Metastore{
+		t:             t,
+		expectMark:    []markRecord{},
+		expectPull:    []pullRecord{},
+		expectPullAll: []map[streams.Processor]streams.Metaitems{},
+	}
+}
+
+// Pull gets and clears the processors metadata.
+func (s *Metastore) Pull(p streams.Processor) (streams.Metaitems, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.expectPull) == 0 {
+		s.t.Error("streams: mock: Unexpected call to Pull")
+		return nil, nil
+	}
+	record := s.expectPull[0]
+	s.expectPull = s.expectPull[1:]
+
+	if record.proc != Anything && record.proc != p {
+		s.t.Errorf("streams: mock: Arguments to Pull did not match expectation: wanted %v, got %v", record.proc, p)
+	}
+
+	if err := s.error(); err != nil {
+		return nil, err
+	}
+
+	return record.items, nil
+}
+
+// PullAll gets and clears all metadata.
+func (s *Metastore) PullAll() (map[streams.Processor]streams.Metaitems, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.expectPullAll) == 0 {
+		s.t.Error("streams: mock: Unexpected call to PullAll")
+		return nil, nil
+	}
+	meta := s.expectPullAll[0]
+	s.expectPullAll = s.expectPullAll[1:]
+
+	if err := s.error(); err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// Mark sets metadata for a processor.
+func (s *Metastore) Mark(p streams.Processor, src streams.Source, meta streams.Metadata) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.expectMark) == 0 {
+		s.t.Error("streams: mock: Unexpected call to Mark")
+		return nil
+	}
+	record := s.expectMark[0]
+	s.expectMark = s.expectMark[1:]
+
+	if (record.proc != Anything && record.proc != p) ||
+		(record.src != Anything && record.src != src) ||
+		!matches(record.meta, meta) {
+		s.t.Errorf("streams: mock: Arguments to Mark did not match expectation: wanted %v:%v:%v, got %v:%v:%v", record.proc, record.src, record.meta, p, src, meta)
+	}
+
+	return s.error()
+}
+
+// ShouldError indicates that an error should be returned on the
+// next operation.
+func (s *Metastore) ShouldError() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.shouldError = true
+}
+
+// error returns an error if one should be returned.
+//
+// The mutex must be held while calling error.
+func (s *Metastore) error() error {
+	if s.shouldError {
+		s.shouldError = false
+		return errors.New("test")
+	}
+
+	return nil
+}
+
+// ExpectMark registers an expectation of a Mark on the Metastore.
+func (s *Metastore) ExpectMark(p, src, meta interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expectMark = append(s.expectMark, markRecord{p, src, meta})
+}
+
+// ExpectPull registers an expectation of a Pull on the Metastore, returning the given items.
+func (s *Metastore) ExpectPull(p interface{}, items streams.Metaitems) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expectPull = append(s.expectPull, pullRecord{p, items})
+}
+
+// ExpectPullAll registers an expectation of a PullAll on the Metastore, returning the given metadata.
+func (s *Metastore) ExpectPullAll(meta map[streams.Processor]streams.Metaitems) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expectPullAll = append(s.expectPullAll, meta)
+}
+
+// AssertExpectations asserts that the expectations were met.
+func (s *Metastore) AssertExpectations() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.expectMark) > 0 {
+		s.t.Error("streams: mock: Expected a call to Mark but got none")
+	}
+
+	if len(s.expectPull) > 0 {
+		s.t.Error("streams: mock: Expected a call to Pull but got none")
+	}
+
+	if len(s.expectPullAll) > 0 {
+		s.t.Error("streams: mock: Expected a call to PullAll but got none")
+	}
+}
@@ -0,0 +1,102 @@
+package mocks_test
+
+import (
+	"testing"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetastore_ImplementsMetastoreInterface(t *testing.T) {
+	var s interface{} = &mocks.Metastore{}
+
+	if _, ok := s.(streams.Metastore); !ok {
+		t.Error("The mock Metastore should implement the streams.Metastore interface.")
+	}
+}
+
+func TestMetastore_HandlesExpectations(t *testing.T) {
+	p := mocks.NewProcessor(t)
+	src := mocks.NewSource(nil, 0)
+	items := streams.Metaitems{{Source: src}}
+	all := map[streams.Processor]streams.Metaitems{p: items}
+	s := mocks.NewMetastore(t)
+
+	s.ExpectMark(p, src, nil)
+	s.ExpectMark(mocks.Anything, mocks.Anything, mocks.Anything)
+	s.ExpectPull(p, items)
+	s.ExpectPullAll(all)
+
+	assert.NoError(t, s.Mark(p, src, nil))
+	assert.NoError(t, s.Mark(nil, nil, nil))
+	pulled, err := s.Pull(p)
+	assert.NoError(t, err)
+	assert.Equal(t, items, pulled)
+	pulledAll, err := s.PullAll()
+	assert.NoError(t, err)
+	assert.Equal(t, all, pulledAll)
+	s.AssertExpectations()
+}
+
+func TestMetastore_WithWrongExpectationOnMark(t *testing.T) {
+	mockT := new(testing.T)
+	s := mocks.NewMetastore(mockT)
+	s.ExpectMark(nil, nil, nil)
+
+	_ = s.Mark(mocks.NewProcessor(t), nil, nil)
+
+	assert.True(t, mockT.Failed())
+}
+
+func TestMetastore_WithWrongExpectationOnPull(t *testing.T) {
+	mockT := new(testing.T)
+	s := mocks.NewMetastore(mockT)
+	s.ExpectPull(nil, nil)
+
+	_, _ = s.Pull(mocks.NewProcessor(t))
+
+	assert.True(t, mockT.Failed())
+}
+
+func TestMetastore_WithoutExpectations(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(s *mocks.Metastore)
+	}{
+		{name: "Mark", fn: func(s *mocks.Metastore) { _ = s.Mark(nil, nil, nil) }},
+		{name: "Pull", fn: func(s *mocks.Metastore) { _, _ = s.Pull(nil) }},
+		{name: "PullAll", fn: func(s *mocks.Metastore) { _, _ = s.PullAll() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockT := new(testing.T)
+			s := mocks.NewMetastore(mockT)
+
+			tt.fn(s)
+
+			assert.True(t, mockT.Failed())
+		})
+	}
+}
+
+func TestMetastore_WithShouldErrorOnPullAll(t *testing.T) {
+	s := mocks.NewMetastore(t)
+	s.ExpectPullAll(nil)
+	s.ShouldError()
+
+	_, err := s.PullAll()
+
+	assert.Error(t, err)
+}
+
+func TestMetastore_WithUnmetExpectations(t *testing.T) {
+	mockT := new(testing.T)
+	s := mocks.NewMetastore(mockT)
+	s.ExpectPullAll(nil)
+
+	s.AssertExpectations()
+
+	assert.True(t, mockT.Failed())
+}
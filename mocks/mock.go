@@ -1,6 +1,19 @@
 package mocks
 
+import (
+	"reflect"
+)
+
 const (
 	// Anything is used where the expectation should not be considered.
 	Anything = "mocks.Anything"
 )
+
+// matches determines if the value matches the expected value.
+func matches(expected, v interface{}) bool {
+	if expected == Anything {
+		return true
+	}
+
+	return reflect.DeepEqual(expected, v)
+}
@@ -0,0 +1,287 @@
+package mocks
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rafalmnich/streams/v6"
+)
+
+var _ = (streams.Monitor)(&Monitor{})
+
+type processedRecord struct {
+	name    string
+	latency interface{}
+	bp      interface{}
+}
+
+// Monitor is a mock Monitor.
+type Monitor struct {
+	t *testing.T
+
+	mu sync.Mutex
+
+	expectProcessed []processedRecord
+	expectFailed    []string
+	expectCommitted []interface{}
+	expectClose     bool
+}
+
+// NewMonitor creates a new mock Monitor instance.
+func NewMonitor(t *testing.T) *Monitor {
+	return &Monitor{
+		t:               t,
+		expectProcessed: []processedRecord{},
+		expectFailed:    []string{},
+		expectCommitted: []interface{}{},
+	}
+}
+
+// Processed adds a processed event to the Monitor.
+func (m *Monitor) Processed(name string, l time.Duration, bp float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.expectProcessed) == 0 {
+		m.t.Error("streams: mock: Unexpected call to Processed")
+		return
+	}
+	record := m.expectProcessed[0]
+	m.expectProcessed = m.expectProcessed[1:]
+
+	if !matches(record.name, name) || !matches(record.latency, l) || !matches(record.bp, bp) {
+		m.t.Errorf("streams: mock: Arguments to Processed did not match expectation: wanted %v:%v:%v, got %v:%v:%v", record.name, record.latency, record.bp, name, l, bp)
+	}
+}
+
+// Failed adds a failed processing event to the Monitor.
+func (m *Monitor) Failed(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.expectFailed) == 0 {
+		m.t.Error("streams: mock: Unexpected call to Failed")
+		return
+	}
+	expected := m.expectFailed[0]
+	m.expectFailed = m.expectFailed[1:]
+
+	if !matches(expected, name) {
+		m.t.Errorf("streams: mock: Arguments to Failed did not match expectation: wanted %v, got %v", expected, name)
+	}
+}
+
+// Committed adds a committed event to the Monitor.
+func (m *Monitor) Committed(l time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.expectCommitted) == 0 {
+		m.t.Error("streams: mock: Unexpected call to Committed")
+		return
+	}
+	latency := m.expectCommitted[0]
+	m.expectCommitted = m.expectCommitted[1:]
+
+	if !matches(latency, l) {
+		m.t.Errorf("streams: mock: Arguments to Committed did not match expectation: wanted %v, got %v", latency, l)
+	}
+}
+
+// Close closes the monitor.
+func (m *Monitor) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.expectClose {
+		m.t.Error("streams: mock: Unexpected call to Close")
+	}
+	m.expectClose = false
+
+	return nil
+}
+
+// ExpectProcessed registers an expectation of a Processed on the Monitor.
+//
+// The latency and back pressure can be Anything, as they are rarely deterministic.
+func (m *Monitor) ExpectProcessed(name string, l, bp interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expectProcessed = append(m.expectProcessed, processedRecord{name, l, bp})
+}
+
+// ExpectFailed registers an expectation of a Failed on the Monitor.
+func (m *Monitor) ExpectFailed(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expectFailed = append(m.expectFailed, name)
+}
+
+// ExpectCommitted registers an expectation of a Committed on the Monitor.
+func (m *Monitor) ExpectCommitted(l interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expectCommitted = append(m.expectCommitted, l)
+}
+
+// ExpectClose registers an expectation of a Close on the Monitor.
+func (m *Monitor) ExpectClose() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expectClose = true
+}
+
+// AssertExpectations asserts that the expectations were met.
+func (m *Monitor) AssertExpectations() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.expectProcessed) > 0 {
+		m.t.Error("streams: mock: Expected a call to Processed but got none")
+	}
+
+	if len(m.expectFailed) > 0 {
+		m.t.Error("streams: mock: Expected a call to Failed but got none")
+	}
+
+	if len(m.expectCommitted) > 0 {
+		m.t.Error("streams: mock: Expected a call to Committed but got none")
+	}
+
+	if m.expectClose {
+		m.t.Error("streams: mock: Expected a call to Close but got none")
+	}
+}
+
+var _ = (streams.Stats)(&Stats{})
+
+type statRecord struct {
+	name  string
+	value interface{}
+	tags  []interface{}
+}
+
+// matches determines if the stat matches the record.
+//
+// A single Anything tag matches any tags.
+func (r statRecord) matches(name string, value interface{}, tags []interface{}) bool {
+	if !matches(r.name, name) || !matches(r.value, value) {
+		return false
+	}
+
+	if len(r.tags) == 1 && r.tags[0] == Anything {
+		return true
+	}
+
+	if len(r.tags) == 0 && len(tags) == 0 {
+		return true
+	}
+
+	return matches(r.tags, tags)
+}
+
+// Stats is a mock Stats.
+type Stats struct {
+	t *testing.T
+
+	mu sync.Mutex
+
+	expectInc    []statRecord
+	expectGauge  []statRecord
+	expectTiming []statRecord
+}
+
+// NewStats creates a new mock Stats instance.
+func NewStats(t *testing.T) *Stats {
+	return &Stats{
+		t:            t,
+		expectInc:    []statRecord{},
+		expectGauge:  []statRecord{},
+		expectTiming: []statRecord{},
+	}
+}
+
+// Inc increments a count by the value.
+func (s *Stats) Inc(name string, value int64, tags ...interface{}) {
+	s.check("Inc", &s.expectInc, name, value, tags)
+}
+
+// Gauge measures the value of a metric.
+func (s *Stats) Gauge(name string, value float64, tags ...interface{}) {
+	s.check("Gauge", &s.expectGauge, name, value, tags)
+}
+
+// Timing sends the value of a Duration.
+func (s *Stats) Timing(name string, value time.Duration, tags ...interface{}) {
+	s.check("Timing", &s.expectTiming, name, value, tags)
+}
+
+// check checks the stat against the next expectation.
+func (s *Stats) check(method string, expect *[]statRecord, name string, value interface{}, tags []interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(*expect) == 0 {
+		s.t.Errorf("streams: mock: Unexpected call to %s", method)
+		return
+	}
+	record := (*expect)[0]
+	*expect = (*expect)[1:]
+
+	if !record.matches(name, value, tags) {
+		s.t.Errorf("streams: mock: Arguments to %s did not match expectation: wanted %v:%v:%v, got %v:%v:%v", method, record.name, record.value, record.tags, name, value, tags)
+	}
+}
+
+// ExpectInc registers an expectation of an Inc on the Stats.
+//
+// The value must be an int64, or Anything. A single Anything tag matches any tags.
+func (s *Stats) ExpectInc(name string, value interface{}, tags ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expectInc = append(s.expectInc, statRecord{name, value, tags})
+}
+
+// ExpectGauge registers an expectation of a Gauge on the Stats.
+//
+// The value must be a float64, or Anything. A single Anything tag matches any tags.
+func (s *Stats) ExpectGauge(name string, value interface{}, tags ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expectGauge = append(s.expectGauge, statRecord{name, value, tags})
+}
+
+// ExpectTiming registers an expectation of a Timing on the Stats.
+//
+// The value must be a time.Duration, or Anything. A single Anything tag matches any tags.
+func (s *Stats) ExpectTiming(name string, value interface{}, tags ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expectTiming = append(s.expectTiming, statRecord{name, value, tags})
+}
+
+// AssertExpectations asserts that the expectations were met.
+func (s *Stats) AssertExpectations() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.expectInc) > 0 {
+		s.t.Error("streams: mock: Expected a call to Inc but got none")
+	}
+
+	if len(s.expectGauge) > 0 {
+		s.t.Error("streams: mock: Expected a call to Gauge but got none")
+	}
+
+	if len(s.expectTiming) > 0 {
+		s.t.Error("streams: mock: Expected a call to Timing but got none")
+	}
+}
@@ -0,0 +1,164 @@
+package mocks_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitor_ImplementsMonitorInterface(t *testing.T) {
+	var m interface{} = &mocks.Monitor{}
+
+	if _, ok := m.(streams.Monitor); !ok {
+		t.Error("The mock Monitor should implement the streams.Monitor interface.")
+	}
+}
+
+func TestMonitor_HandlesExpectations(t *testing.T) {
+	m := mocks.NewMonitor(t)
+
+	m.ExpectProcessed("test", time.Second, 0.5)
+	m.ExpectProcessed("test", mocks.Anything, mocks.Anything)
+	m.ExpectFailed("test")
+	m.ExpectCommitted(mocks.Anything)
+	m.ExpectClose()
+
+	m.Processed("test", time.Second, 0.5)
+	m.Processed("test", time.Millisecond, -1)
+	m.Failed("test")
+	m.Committed(time.Second)
+	assert.NoError(t, m.Close())
+	m.AssertExpectations()
+}
+
+func TestMonitor_WithWrongExpectationOnProcessed(t *testing.T) {
+	mockT := new(testing.T)
+	m := mocks.NewMonitor(mockT)
+	m.ExpectProcessed("test", mocks.Anything, mocks.Anything)
+
+	m.Processed("other", time.Second, 0.5)
+
+	assert.True(t, mockT.Failed())
+}
+
+func TestMonitor_WithWrongExpectationOnFailed(t *testing.T) {
+	mockT := new(testing.T)
+	m := mocks.NewMonitor(mockT)
+	m.ExpectFailed("test")
+
+	m.Failed("other")
+
+	assert.True(t, mockT.Failed())
+}
+
+func TestMonitor_WithoutExpectations(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(m *mocks.Monitor)
+	}{
+		{name: "Processed", fn: func(m *mocks.Monitor) { m.Processed("test", 0, 0) }},
+		{name: "Failed", fn: func(m *mocks.Monitor) { m.Failed("test") }},
+		{name: "Committed", fn: func(m *mocks.Monitor) { m.Committed(0) }},
+		{name: "Close", fn: func(m *mocks.Monitor) { _ = m.Close() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockT := new(testing.T)
+			m := mocks.NewMonitor(mockT)
+
+			tt.fn(m)
+
+			assert.True(t, mockT.Failed())
+		})
+	}
+}
+
+func TestMonitor_WithUnmetExpectations(t *testing.T) {
+	mockT := new(testing.T)
+	m := mocks.NewMonitor(mockT)
+	m.ExpectCommitted(mocks.Anything)
+
+	m.AssertExpectations()
+
+	assert.True(t, mockT.Failed())
+}
+
+func TestStats_ImplementsStatsInterface(t *testing.T) {
+	var s interface{} = &mocks.Stats{}
+
+	if _, ok := s.(streams.Stats); !ok {
+		t.Error("The mock Stats should implement the streams.Stats interface.")
+	}
+}
+
+func TestStats_HandlesExpectations(t *testing.T) {
+	s := mocks.NewStats(t)
+
+	s.ExpectInc("test", int64(1), "name", "test")
+	s.ExpectGauge("test", 0.5)
+	s.ExpectTiming("test", mocks.Anything, mocks.Anything)
+
+	s.Inc("test", 1, "name", "test")
+	s.Gauge("test", 0.5)
+	s.Timing("test", time.Second, "name", "test")
+	s.AssertExpectations()
+}
+
+func TestStats_WithWrongExpectations(t *testing.T) {
+	tests := []struct {
+		name   string
+		expect func(s *mocks.Stats)
+		fn     func(s *mocks.Stats)
+	}{
+		{
+			name:   "Inc Value",
+			expect: func(s *mocks.Stats) { s.ExpectInc("test", int64(1)) },
+			fn:     func(s *mocks.Stats) { s.Inc("test", 2) },
+		},
+		{
+			name:   "Gauge Name",
+			expect: func(s *mocks.Stats) { s.ExpectGauge("test", mocks.Anything) },
+			fn:     func(s *mocks.Stats) { s.Gauge("other", 1) },
+		},
+		{
+			name:   "Timing Tags",
+			expect: func(s *mocks.Stats) { s.ExpectTiming("test", mocks.Anything, "name", "test") },
+			fn:     func(s *mocks.Stats) { s.Timing("test", time.Second) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockT := new(testing.T)
+			s := mocks.NewStats(mockT)
+			tt.expect(s)
+
+			tt.fn(s)
+
+			assert.True(t, mockT.Failed())
+		})
+	}
+}
+
+func TestStats_WithoutExpectations(t *testing.T) {
+	mockT := new(testing.T)
+	s := mocks.NewStats(mockT)
+
+	s.Inc("test", 1)
+
+	assert.True(t, mockT.Failed())
+}
+
+func TestStats_WithUnmetExpectations(t *testing.T) {
+	mockT := new(testing.T)
+	s := mocks.NewStats(mockT)
+	s.ExpectGauge("test", mocks.Anything)
+
+	s.AssertExpectations()
+
+	assert.True(t, mockT.Failed())
+}
@@ -0,0 +1,184 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/rafalmnich/streams/v6"
+)
+
+var _ = (streams.Processor)(&Processor{})
+
+// Processor is a mock Processor.
+type Processor struct {
+	t *testing.T
+
+	mu   sync.Mutex
+	pipe streams.Pipe
+
+	shouldError bool
+
+	expectProcess []record
+	expectClose   bool
+}
+
+// NewProcessor creates a new mock Processor instance.
+func NewProcessor(t *testing.T) *Processor {
+	return &Processor{
+		t:             t,
+		expectProcess: []record{},
+	}
+}
+
+// WithPipe sets the pipe on the Processor.
+func (p *Processor) WithPipe(pipe streams.Pipe) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pipe = pipe
+}
+
+// Pipe gets the pipe set on the Processor.
+func (p *Processor) Pipe() streams.Pipe {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.pipe
+}
+
+// Process processes the stream Message.
+func (p *Processor) Process(msg streams.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.expectProcess) == 0 {
+		p.t.Error("streams: mock: Unexpected call to Process")
+		return nil
+	}
+	record := p.expectProcess[0]
+	p.expectProcess = p.expectProcess[1:]
+
+	if !matches(record.key, msg.Key) || !matches(record.value, msg.Value) {
+		p.t.Errorf("streams: mock: Arguments to Process did not match expectation: wanted %v:%v, got %v:%v", record.key, record.value, msg.Key, msg.Value)
+	}
+
+	return p.error()
+}
+
+// Close closes the processor.
+func (p *Processor) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.expectClose {
+		p.t.Error("streams: mock: Unexpected call to Close")
+	}
+	p.expectClose = false
+
+	return p.error()
+}
+
+// ShouldError indicates that an error should be returned on the
+// next operation.
+func (p *Processor) ShouldError() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.shouldError = true
+}
+
+// error returns an error if one should be returned.
+//
+// The mutex must be held while calling error.
+func (p *Processor) error() error {
+	if p.shouldError {
+		p.shouldError = false
+		return errors.New("test")
+	}
+
+	return nil
+}
+
+// ExpectProcess registers an expectation of a Process on the Processor.
+func (p *Processor) ExpectProcess(k, v interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.expectProcess = append(p.expectProcess, record{k, v, -1})
+}
+
+// ExpectClose registers an expectation of a Close on the Processor.
+func (p *Processor) ExpectClose() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.expectClose = true
+}
+
+// AssertExpectations asserts that the expectations were met.
+func (p *Processor) AssertExpectations() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.expectProcess) > 0 {
+		p.t.Error("streams: mock: Expected a call to Process but got none")
+	}
+
+	if p.expectClose {
+		p.t.Error("streams: mock: Expected a call to Close but got none")
+	}
+}
+
+var _ = (streams.Committer)(&Committer{})
+
+// Committer is a mock Committer.
+type Committer struct {
+	Processor
+
+	expectCommit bool
+}
+
+// NewCommitter creates a new mock Committer instance.
+func NewCommitter(t *testing.T) *Committer {
+	return &Committer{
+		Processor: Processor{
+			t:             t,
+			expectProcess: []record{},
+		},
+	}
+}
+
+// Commit commits the processors batch.
+func (c *Committer) Commit(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.expectCommit {
+		c.t.Error("streams: mock: Unexpected call to Commit")
+	}
+	c.expectCommit = false
+
+	return c.error()
+}
+
+// ExpectCommit registers an expectation of a Commit on the Committer.
+func (c *Committer) ExpectCommit() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expectCommit = true
+}
+
+// AssertExpectations asserts that the expectations were met.
+func (c *Committer) AssertExpectations() {
+	c.Processor.AssertExpectations()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.expectCommit {
+		c.t.Error("streams: mock: Expected a call to Commit but got none")
+	}
+}
@@ -0,0 +1,139 @@
+package mocks_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessor_ImplementsProcessorInterface(t *testing.T) {
+	var p interface{} = &mocks.Processor{}
+
+	if _, ok := p.(streams.Processor); !ok {
+		t.Error("The mock Processor should implement the streams.Processor interface.")
+	}
+}
+
+func TestProcessor_WithPipe(t *testing.T) {
+	pipe := mocks.NewPipe(t)
+	p := mocks.NewProcessor(t)
+
+	p.WithPipe(pipe)
+
+	assert.Equal(t, pipe, p.Pipe())
+}
+
+func TestProcessor_HandlesExpectations(t *testing.T) {
+	p := mocks.NewProcessor(t)
+
+	p.ExpectProcess("test", "test")
+	p.ExpectProcess(mocks.Anything, mocks.Anything)
+	p.ExpectClose()
+
+	assert.NoError(t, p.Process(streams.NewMessage("test", "test")))
+	assert.NoError(t, p.Process(streams.NewMessage(1, []byte{1})))
+	assert.NoError(t, p.Close())
+	p.AssertExpectations()
+}
+
+func TestProcessor_WithoutExpectationOnProcess(t *testing.T) {
+	mockT := new(testing.T)
+	p := mocks.NewProcessor(mockT)
+
+	_ = p.Process(streams.NewMessage("test", "test"))
+
+	assert.True(t, mockT.Failed())
+}
+
+func TestProcessor_WithWrongExpectationOnProcess(t *testing.T) {
+	mockT := new(testing.T)
+	p := mocks.NewProcessor(mockT)
+	p.ExpectProcess(1, 1)
+
+	_ = p.Process(streams.NewMessage("test", "test"))
+
+	assert.True(t, mockT.Failed())
+}
+
+func TestProcessor_WithShouldErrorOnProcess(t *testing.T) {
+	p := mocks.NewProcessor(t)
+	p.ExpectProcess("test", "test")
+	p.ShouldError()
+
+	err := p.Process(streams.NewMessage("test", "test"))
+
+	assert.Error(t, err)
+}
+
+func TestProcessor_WithoutExpectationOnClose(t *testing.T) {
+	mockT := new(testing.T)
+	p := mocks.NewProcessor(mockT)
+
+	_ = p.Close()
+
+	assert.True(t, mockT.Failed())
+}
+
+func TestProcessor_WithUnmetExpectations(t *testing.T) {
+	mockT := new(testing.T)
+	p := mocks.NewProcessor(mockT)
+	p.ExpectProcess("test", "test")
+	p.ExpectClose()
+
+	p.AssertExpectations()
+
+	assert.True(t, mockT.Failed())
+}
+
+func TestCommitter_ImplementsCommitterInterface(t *testing.T) {
+	var c interface{} = &mocks.Committer{}
+
+	if _, ok := c.(streams.Committer); !ok {
+		t.Error("The mock Committer should implement the streams.Committer interface.")
+	}
+}
+
+func TestCommitter_HandlesExpectations(t *testing.T) {
+	c := mocks.NewCommitter(t)
+
+	c.ExpectProcess("test", "test")
+	c.ExpectCommit()
+	c.ExpectClose()
+
+	assert.NoError(t, c.Process(streams.NewMessage("test", "test")))
+	assert.NoError(t, c.Commit(context.Background()))
+	assert.NoError(t, c.Close())
+	c.AssertExpectations()
+}
+
+func TestCommitter_WithoutExpectationOnCommit(t *testing.T) {
+	mockT := new(testing.T)
+	c := mocks.NewCommitter(mockT)
+
+	_ = c.Commit(context.Background())
+
+	assert.True(t, mockT.Failed())
+}
+
+func TestCommitter_WithShouldErrorOnCommit(t *testing.T) {
+	c := mocks.NewCommitter(t)
+	c.ExpectCommit()
+	c.ShouldError()
+
+	err := c.Commit(context.Background())
+
+	assert.Error(t, err)
+}
+
+func TestCommitter_WithUnmetExpectations(t *testing.T) {
+	mockT := new(testing.T)
+	c := mocks.NewCommitter(mockT)
+	c.ExpectCommit()
+
+	c.AssertExpectations()
+
+	assert.True(t, mockT.Failed())
+}
@@ -1,12 +1,13 @@
 package mocks
 
 import (
+	"context"
 	"time"
 
 	"github.com/rafalmnich/streams/v6"
 )
 
-var _ streams.Source = (*Source)(nil)
+var _ streams.ContextSource = (*Source)(nil)
 
 // Source is a test source to be used with streams command-level tests.
 // It allows consumption of provided set of messages and counts commits.
@@ -40,6 +41,18 @@ func (s *Source) Consume() (streams.Message, error) {
 	}
 }
 
+// ConsumeContext gets the next record from the Source, blocking until
+// a record is available or the context is done.
+func (s *Source) ConsumeContext(ctx context.Context) (streams.Message, error) {
+	select {
+	case msg := <-s.ch:
+		return msg.WithMetadata(s, nil), nil
+
+	case <-ctx.Done():
+		return streams.EmptyMessage, ctx.Err()
+	}
+}
+
 // Commit marks the consumed records as processed.
 // Once the counted commits reach expected level an exit signal is emitted.
 func (s *Source) Commit(interface{}) error {
@@ -0,0 +1,195 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/rafalmnich/streams/v6"
+)
+
+var _ = (streams.Supervisor)(&Supervisor{})
+
+// Supervisor is a mock Supervisor.
+type Supervisor struct {
+	t *testing.T
+
+	mu    sync.Mutex
+	ctx   context.Context
+	mon   streams.Monitor
+	pumps map[streams.Node]streams.Pump
+
+	shouldError bool
+
+	expectStart  bool
+	expectCommit []interface{}
+	expectClose  bool
+}
+
+// NewSupervisor creates a new mock Supervisor instance.
+func NewSupervisor(t *testing.T) *Supervisor {
+	return &Supervisor{
+		t:            t,
+		expectCommit: []interface{}{},
+	}
+}
+
+// WithContext sets the context.
+func (s *Supervisor) WithContext(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ctx = ctx
+}
+
+// Context gets the context set on the Supervisor.
+func (s *Supervisor) Context() context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ctx
+}
+
+// WithMonitor sets the Monitor.
+func (s *Supervisor) WithMonitor(mon streams.Monitor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mon = mon
+}
+
+// Monitor gets the Monitor set on the Supervisor.
+func (s *Supervisor) Monitor() streams.Monitor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.mon
+}
+
+// WithPumps sets a map of Pumps.
+func (s *Supervisor) WithPumps(pumps map[streams.Node]streams.Pump) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pumps = pumps
+}
+
+// Pumps gets the Pumps set on the Supervisor.
+func (s *Supervisor) Pumps() map[streams.Node]streams.Pump {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pumps
+}
+
+// Start starts the supervisor.
+func (s *Supervisor) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.expectStart {
+		s.t.Error("streams: mock: Unexpected call to Start")
+	}
+	s.expectStart = false
+
+	return s.error()
+}
+
+// Commit performs a global commit sequence.
+func (s *Supervisor) Commit(caller streams.Processor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.expectCommit) == 0 {
+		s.t.Error("streams: mock: Unexpected call to Commit")
+		return nil
+	}
+	expected := s.expectCommit[0]
+	s.expectCommit = s.expectCommit[1:]
+
+	if expected != Anything && expected != caller {
+		s.t.Errorf("streams: mock: Arguments to Commit did not match expectation: wanted %v, got %v", expected, caller)
+	}
+
+	return s.error()
+}
+
+// Close closes the supervisor.
+func (s *Supervisor) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.expectClose {
+		s.t.Error("streams: mock: Unexpected call to Close")
+	}
+	s.expectClose = false
+
+	return s.error()
+}
+
+// ShouldError indicates that an error should be returned on the
+// next operation.
+func (s *Supervisor) ShouldError() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.shouldError = true
+}
+
+// error returns an error if one should be returned.
+//
+// The mutex must be held while calling error.
+func (s *Supervisor) error() error {
+	if s.shouldError {
+		s.shouldError = false
+		return errors.New("test")
+	}
+
+	return nil
+}
+
+// ExpectStart registers an expectation of a Start on the Supervisor.
+func (s *Supervisor) ExpectStart() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expectStart = true
+}
+
+// ExpectCommit registers an expectation of a Commit on the Supervisor.
+//
+// The caller is the Processor expected to trigger the commit, nil for
+// a commit that is not triggered by a Processor, or Anything.
+func (s *Supervisor) ExpectCommit(caller interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expectCommit = append(s.expectCommit, caller)
+}
+
+// ExpectClose registers an expectation of a Close on the Supervisor.
+func (s *Supervisor) ExpectClose() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expectClose = true
+}
+
+// AssertExpectations asserts that the expectations were met.
+func (s *Supervisor) AssertExpectations() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.expectStart {
+		s.t.Error("streams: mock: Expected a call to Start but got none")
+	}
+
+	if len(s.expectCommit) > 0 {
+		s.t.Error("streams: mock: Expected a call to Commit but got none")
+	}
+
+	if s.expectClose {
+		s.t.Error("streams: mock: Expected a call to Close but got none")
+	}
+}
@@ -0,0 +1,103 @@
+package mocks_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupervisor_ImplementsSupervisorInterface(t *testing.T) {
+	var s interface{} = &mocks.Supervisor{}
+
+	if _, ok := s.(streams.Supervisor); !ok {
+		t.Error("The mock Supervisor should implement the streams.Supervisor interface.")
+	}
+}
+
+func TestSupervisor_Setters(t *testing.T) {
+	ctx := context.Background()
+	mon := mocks.NewMonitor(t)
+	pumps := map[streams.Node]streams.Pump{}
+	s := mocks.NewSupervisor(t)
+
+	s.WithContext(ctx)
+	s.WithMonitor(mon)
+	s.WithPumps(pumps)
+
+	assert.Equal(t, ctx, s.Context())
+	assert.Equal(t, mon, s.Monitor())
+	assert.Equal(t, pumps, s.Pumps())
+}
+
+func TestSupervisor_HandlesExpectations(t *testing.T) {
+	p := mocks.NewProcessor(t)
+	s := mocks.NewSupervisor(t)
+
+	s.ExpectStart()
+	s.ExpectCommit(p)
+	s.ExpectCommit(nil)
+	s.ExpectCommit(mocks.Anything)
+	s.ExpectClose()
+
+	assert.NoError(t, s.Start())
+	assert.NoError(t, s.Commit(p))
+	assert.NoError(t, s.Commit(nil))
+	assert.NoError(t, s.Commit(p))
+	assert.NoError(t, s.Close())
+	s.AssertExpectations()
+}
+
+func TestSupervisor_WithWrongExpectationOnCommit(t *testing.T) {
+	mockT := new(testing.T)
+	s := mocks.NewSupervisor(mockT)
+	s.ExpectCommit(nil)
+
+	_ = s.Commit(mocks.NewProcessor(t))
+
+	assert.True(t, mockT.Failed())
+}
+
+func TestSupervisor_WithoutExpectations(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(s *mocks.Supervisor) error
+	}{
+		{name: "Start", fn: func(s *mocks.Supervisor) error { return s.Start() }},
+		{name: "Commit", fn: func(s *mocks.Supervisor) error { return s.Commit(nil) }},
+		{name: "Close", fn: func(s *mocks.Supervisor) error { return s.Close() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockT := new(testing.T)
+			s := mocks.NewSupervisor(mockT)
+
+			_ = tt.fn(s)
+
+			assert.True(t, mockT.Failed())
+		})
+	}
+}
+
+func TestSupervisor_WithShouldErrorOnCommit(t *testing.T) {
+	s := mocks.NewSupervisor(t)
+	s.ExpectCommit(nil)
+	s.ShouldError()
+
+	err := s.Commit(nil)
+
+	assert.Error(t, err)
+}
+
+func TestSupervisor_WithUnmetExpectations(t *testing.T) {
+	mockT := new(testing.T)
+	s := mocks.NewSupervisor(mockT)
+	s.ExpectCommit(nil)
+
+	s.AssertExpectations()
+
+	assert.True(t, mockT.Failed())
+}
@@ -58,6 +58,10 @@ func (m *MockMonitor) Processed(name string, l time.Duration, bp float64) {
 	m.Called(name, l, bp)
 }
 
+func (m *MockMonitor) Failed(name string) {
+	m.Called(name)
+}
+
 func (m *MockMonitor) Committed(l time.Duration) {
 	m.Called(l)
 }
@@ -178,6 +182,17 @@ func (p *MockPump) WithLock(func() error) error {
 	return args.Error(0)
 }
 
+var _ = (streams.BatchPump)(&MockBatchPump{})
+
+type MockBatchPump struct {
+	MockPump
+}
+
+func (p *MockBatchPump) AcceptBatch(msgs []streams.Message) error {
+	args := p.Called(msgs)
+	return args.Error(0)
+}
+
 var _ = (streams.Processor)(&MockProcessor{})
 
 type MockProcessor struct {
@@ -198,6 +213,17 @@ func (p *MockProcessor) Close() error {
 	return args.Error(0)
 }
 
+var _ = (streams.BatchProcessor)(&MockBatchProcessor{})
+
+type MockBatchProcessor struct {
+	MockProcessor
+}
+
+func (p *MockBatchProcessor) ProcessBatch(msgs []streams.Message) error {
+	args := p.Called(msgs)
+	return args.Error(0)
+}
+
 var _ = (streams.Processor)(&MockCommitter{})
 var _ = (streams.Committer)(&MockCommitter{})
 
@@ -245,6 +271,45 @@ func (s *MockSource) Close() error {
 	return args.Error(0)
 }
 
+var _ = (streams.ContextSource)(&MockContextSource{})
+
+type MockContextSource struct {
+	MockSource
+}
+
+func (s *MockContextSource) ConsumeContext(ctx context.Context) (streams.Message, error) {
+	args := s.Called(ctx)
+	if fn, ok := args.Get(0).(func(context.Context) streams.Message); ok {
+		return fn(ctx), args.Error(1)
+	}
+	return args.Get(0).(streams.Message), args.Error(1)
+}
+
+var _ = (streams.BatchSource)(&MockBatchSource{})
+
+type MockBatchSource struct {
+	MockSource
+}
+
+func (s *MockBatchSource) ConsumeBatch() ([]streams.Message, error) {
+	args := s.Called()
+	return args.Get(0).([]streams.Message), args.Error(1)
+}
+
+var _ = (streams.ContextBatchSource)(&MockContextBatchSource{})
+
+type MockContextBatchSource struct {
+	MockBatchSource
+}
+
+func (s *MockContextBatchSource) ConsumeBatchContext(ctx context.Context) ([]streams.Message, error) {
+	args := s.Called(ctx)
+	if fn, ok := args.Get(0).(func(context.Context) []streams.Message); ok {
+		return fn(ctx), args.Error(1)
+	}
+	return args.Get(0).([]streams.Message), args.Error(1)
+}
+
 type MockTask struct {
 	mock.Mock
 
@@ -264,6 +329,32 @@ func (t *MockTask) OnError(fn streams.ErrorFunc) {
 	t.Called(fn)
 }
 
+func (t *MockTask) Pause() error {
+	return t.Called().Error(0)
+}
+
+func (t *MockTask) Resume() error {
+	return t.Called().Error(0)
+}
+
+func (t *MockTask) Shutdown(ctx context.Context) error {
+	t.closeCalled = time.Now()
+
+	return t.Called(ctx).Error(0)
+}
+
+func (t *MockTask) Done() <-chan struct{} {
+	return t.Called().Get(0).(chan struct{})
+}
+
+func (t *MockTask) Wait() error {
+	return t.Called().Error(0)
+}
+
+func (t *MockTask) Status() streams.TaskStatus {
+	return t.Called().Get(0).(streams.TaskStatus)
+}
+
 func (t *MockTask) Close() error {
 	t.closeCalled = time.Now()
 
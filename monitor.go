@@ -1,22 +1,48 @@
 package streams
 
 import (
+	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// monitorShardBits is the number of bits of the shard index of the counters of a node.
+const monitorShardBits = 3
+
+// monitorShards is the number of shards the counters of a node are spread over,
+// limiting the contention between pumps reporting on the same node.
+const monitorShards = 1 << monitorShardBits
+
+// monitorQueueSize is the number of intervals that can wait to be flushed to the
+// Stats before the events of an interval are dropped.
+const monitorQueueSize = 16
+
+// noBackPressure is the back pressure of a node that has not reported any.
+var noBackPressure = math.Float64bits(-1)
+
 type event struct {
 	EventType    string
 	Name         string
 	Count        int64
+	Errors       int64
 	Latency      time.Duration
 	BackPressure float64
 }
 
+// aggregate represents the events of a node aggregated over an interval.
+type aggregate struct {
+	event
+
+	hist histogram
+}
+
 // Monitor represents a stream event collector.
 type Monitor interface {
 	// Processed adds a processed event to the Monitor.
 	Processed(name string, l time.Duration, bp float64)
+	// Failed adds a failed processing event to the Monitor.
+	Failed(name string)
 	// Committed adds a committed event to the Monitor.
 	Committed(l time.Duration)
 	// Close closes the monitor.
@@ -33,25 +59,129 @@ type Stats interface {
 	Timing(name string, value time.Duration, tags ...interface{})
 }
 
+// monitorShard represents a shard of the counters of a node, padded to its own cache line.
+type monitorShard struct {
+	count   int64
+	errors  int64
+	latency int64
+
+	_ [40]byte
+}
+
+// counters represents the counters of a node over an interval, updated atomically.
+type counters struct {
+	shards [monitorShards]monitorShard
+	hist   atomicHistogram
+	bp     uint64
+
+	eventType string
+	name      string
+}
+
+func newCounters(eventType, name string) *counters {
+	return &counters{
+		bp:        noBackPressure,
+		eventType: eventType,
+		name:      name,
+	}
+}
+
+// processed counts a processed event.
+func (c *counters) processed(l time.Duration, bp float64) {
+	s := &c.shards[shardOf(uint64(l))]
+	atomic.AddInt64(&s.count, 1)
+	atomic.AddInt64(&s.latency, int64(l))
+
+	c.hist.record(l)
+
+	if bp >= 0 {
+		atomic.StoreUint64(&c.bp, math.Float64bits(bp))
+	}
+}
+
+// failed counts a failed event.
+func (c *counters) failed() {
+	s := &c.shards[shardOf(uint64(nanotime()))]
+	atomic.AddInt64(&s.errors, 1)
+}
+
+// snapshot resets the counters, returning the events counted since the last snapshot.
+//
+// The counters are not reset as a whole, so an event counted concurrently
+// may be split across two consecutive snapshots.
+func (c *counters) snapshot() *aggregate {
+	agg := &aggregate{event: event{
+		EventType: c.eventType,
+		Name:      c.name,
+	}}
+
+	for i := range c.shards {
+		s := &c.shards[i]
+		agg.Count += atomic.SwapInt64(&s.count, 0)
+		agg.Errors += atomic.SwapInt64(&s.errors, 0)
+		agg.Latency += time.Duration(atomic.SwapInt64(&s.latency, 0))
+	}
+	agg.BackPressure = math.Float64frombits(atomic.SwapUint64(&c.bp, noBackPressure))
+	c.hist.snapshot(&agg.hist)
+
+	return agg
+}
+
+// shardOf returns the shard of a value, spreading close values across shards.
+func shardOf(v uint64) int {
+	return int((v * 0x9E3779B97F4A7C15) >> (64 - monitorShardBits))
+}
+
+// snapshot represents the events of all nodes over an interval.
+type snapshot struct {
+	aggs     []*aggregate
+	overhead time.Duration
+}
+
+// events returns the number of events in the snapshot.
+func (s snapshot) events() int64 {
+	var n int64
+	for _, agg := range s.aggs {
+		n += agg.Count + agg.Errors
+	}
+
+	return n
+}
+
+// monitor aggregates the events of the nodes without blocking the nodes reporting them.
+//
+// The events are counted atomically, and snapshots of the counters are queued to
+// be flushed to the Stats every interval. The events of an interval are dropped,
+// rather than blocking, when the queue is full because the Stats are too slow.
 type monitor struct {
 	stats Stats
 
-	eventWg sync.WaitGroup
-	eventCh chan event
+	nodes sync.Map
+
+	mu    sync.Mutex
+	order []*counters
+
+	dropped int64
+
+	done    chan struct{}
+	runWg   sync.WaitGroup
 	flushWg sync.WaitGroup
-	flushCh chan []event
+	flushCh chan snapshot
 }
 
 // NewMonitor creates a new Monitor.
+//
+// Besides the node metrics, the monitor reports the events it dropped, and
+// the overhead of flushing its metrics.
 func NewMonitor(stats Stats, interval time.Duration) Monitor {
 	m := &monitor{
 		stats:   stats,
-		eventCh: make(chan event, 1000),
-		flushCh: make(chan []event, 1000),
+		done:    make(chan struct{}),
+		flushCh: make(chan snapshot, monitorQueueSize),
 	}
 
-	m.eventWg.Add(1)
-	go m.runCache(interval)
+	m.runWg.Add(1)
+	go m.run(interval)
 
 	m.flushWg.Add(1)
 	go m.runFlush()
@@ -59,103 +189,134 @@ func NewMonitor(stats Stats, interval time.Duration) Monitor {
 	return m
 }
 
-func (m *monitor) runCache(interval time.Duration) {
-	defer m.eventWg.Done()
+// counters returns the counters of the named node, creating them if needed.
+func (m *monitor) counters(eventType, name string) *counters {
+	if c, ok := m.nodes.Load(name); ok {
+		return c.(*counters)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, loaded := m.nodes.LoadOrStore(name, newCounters(eventType, name))
+	if !loaded {
+		m.order = append(m.order, c.(*counters))
+	}
+
+	return c.(*counters)
+}
+
+func (m *monitor) run(interval time.Duration) {
+	defer m.runWg.Done()
 
-	var cache []event
 	timer := time.NewTicker(interval)
 	defer timer.Stop()
 
 	for {
 		select {
-		case e, ok := <-m.eventCh:
-			if !ok {
-				if len(cache) > 0 {
-					m.flushCh <- cache
-				}
-
-				return
-			}
-
-			i := eventIndexOf(e, cache)
-			if i < 0 {
-				cache = append(cache, e)
-				continue
+		case <-m.done:
+			if s := m.snapshot(); s.events() > 0 {
+				m.flushCh <- s
 			}
 
-			cached := cache[i]
-			e.Count += cached.Count
-			e.Latency += cached.Latency
-			cache[i] = e
+			return
 
 		case <-timer.C:
-			m.flushCh <- cache
+			s := m.snapshot()
 
-			cache = []event{}
+			select {
+			case m.flushCh <- s:
+			default:
+				atomic.AddInt64(&m.dropped, s.events())
+			}
 		}
 	}
 }
 
-func eventIndexOf(v event, arr []event) int {
-	for i, e := range arr {
-		if e.Name == v.Name {
-			return i
-		}
+// snapshot takes a snapshot of the counters of all nodes.
+func (m *monitor) snapshot() snapshot {
+	start := nanotime()
+
+	m.mu.Lock()
+	order := m.order
+	m.mu.Unlock()
+
+	aggs := make([]*aggregate, 0, len(order))
+	for _, c := range order {
+		aggs = append(aggs, c.snapshot())
 	}
 
-	return -1
+	return snapshot{
+		aggs:     aggs,
+		overhead: time.Duration(nanotime() - start),
+	}
 }
 
 func (m *monitor) runFlush() {
 	defer m.flushWg.Done()
 
-	for cache := range m.flushCh {
-		for _, event := range cache {
-			tags := []interface{}{"name", event.Name}
-			switch event.EventType {
+	for s := range m.flushCh {
+		start := nanotime()
+
+		for _, agg := range s.aggs {
+			tags := []interface{}{"name", agg.Name}
+			switch agg.EventType {
 			case "node":
-				m.stats.Timing("node.latency", time.Duration(int64(event.Latency)/event.Count), tags...)
-				m.stats.Inc("node.throughput", event.Count, tags...)
-				if event.BackPressure >= 0 {
-					m.stats.Gauge("node.back-pressure", event.BackPressure, tags...)
+				if agg.Errors > 0 {
+					m.stats.Inc("node.errors", agg.Errors, tags...)
+				}
+				if agg.Count == 0 {
+					continue
+				}
+
+				m.stats.Timing("node.latency", time.Duration(int64(agg.Latency)/agg.Count), tags...)
+				m.stats.Timing("node.latency.p50", agg.hist.quantile(0.5), tags...)
+				m.stats.Timing("node.latency.p95", agg.hist.quantile(0.95), tags...)
+				m.stats.Timing("node.latency.p99", agg.hist.quantile(0.99), tags...)
+				m.stats.Timing("node.latency.max", agg.hist.max, tags...)
+				m.stats.Inc("node.throughput", agg.Count, tags...)
+				if agg.BackPressure >= 0 {
+					m.stats.Gauge("node.back-pressure", agg.BackPressure, tags...)
 				}
 
 			case "commit":
-				m.stats.Timing("commit.latency", time.Duration(int64(event.Latency)/event.Count))
-				m.stats.Inc("commit.commits", event.Count, tags...)
+				if agg.Count == 0 {
+					continue
+				}
+
+				m.stats.Timing("commit.latency", time.Duration(int64(agg.Latency)/agg.Count))
+				m.stats.Inc("commit.commits", agg.Count, tags...)
 			}
 		}
 
-		m.stats.Gauge("monitor.back-pressure", float64(len(m.eventCh))/float64(cap(m.eventCh))*100)
+		if dropped := atomic.SwapInt64(&m.dropped, 0); dropped > 0 {
+			m.stats.Inc("monitor.dropped", dropped)
+		}
+		m.stats.Gauge("monitor.back-pressure", float64(len(m.flushCh))/float64(cap(m.flushCh))*100)
+		m.stats.Timing("monitor.overhead", s.overhead+time.Duration(nanotime()-start))
 	}
 }
 
 // Processed adds a processed event to the monitor.
 func (m *monitor) Processed(name string, l time.Duration, bp float64) {
-	m.eventCh <- event{
-		EventType:    "node",
-		Name:         name,
-		Count:        1,
-		Latency:      l,
-		BackPressure: bp,
-	}
+	m.counters("node", name).processed(l, bp)
+}
+
+// Failed adds a failed processing event to the Monitor.
+func (m *monitor) Failed(name string) {
+	m.counters("node", name).failed()
 }
 
 // Committed adds a committed event to the Monitor.
 func (m *monitor) Committed(l time.Duration) {
-	m.eventCh <- event{
-		EventType: "commit",
-		Name:      "streams:commit",
-		Count:     1,
-		Latency:   l,
-	}
+	m.counters("commit", "streams:commit").processed(l, -1)
 }
 
-// Close closes the monitor.
+// Close closes the monitor, flushing the outstanding events.
 func (m *monitor) Close() error {
-	close(m.eventCh)
+	close(m.done)
 
-	m.eventWg.Wait()
+	m.runWg.Wait()
 
 	close(m.flushCh)
 
@@ -168,6 +329,8 @@ type nullMonitor struct{}
 
 func (nullMonitor) Processed(name string, l time.Duration, bp float64) {}
 
+func (nullMonitor) Failed(name string) {}
+
 func (nullMonitor) Committed(l time.Duration) {}
 
 func (nullMonitor) Close() error {
@@ -12,6 +12,13 @@ func TestNullMonitor_Processed(t *testing.T) {
 	mon.Processed("test", time.Second, 50)
 }
 
+func TestNullMonitor_Failed(t *testing.T) {
+	mon := nullMonitor{}
+	defer mon.Close()
+
+	mon.Failed("test")
+}
+
 func TestMonitor_Committed(t *testing.T) {
 	mon := nullMonitor{}
 	defer mon.Close()
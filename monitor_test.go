@@ -1,10 +1,12 @@
 package streams_test
 
 import (
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -21,7 +23,12 @@ func TestMonitor_Processed(t *testing.T) {
 	stat.On("Inc", "node.throughput", int64(1), mock.Anything)
 	stat.On("Gauge", "node.back-pressure", float64(50), mock.Anything)
 	stat.On("Timing", "node.latency", time.Second, mock.Anything)
+	stat.On("Timing", "node.latency.p50", time.Second, mock.Anything)
+	stat.On("Timing", "node.latency.p95", time.Second, mock.Anything)
+	stat.On("Timing", "node.latency.p99", time.Second, mock.Anything)
+	stat.On("Timing", "node.latency.max", time.Second, mock.Anything)
 	stat.On("Gauge", "monitor.back-pressure", mock.Anything, mock.Anything)
+	stat.On("Timing", "monitor.overhead", mock.Anything, mock.Anything)
 
 	mon := streams.NewMonitor(stat, time.Microsecond)
 
@@ -34,11 +41,54 @@ func TestMonitor_Processed(t *testing.T) {
 	stat.AssertExpectations(t)
 }
 
+func TestMonitor_ProcessedPercentiles(t *testing.T) {
+	stats := mocks.NewStats(t)
+	stats.ExpectTiming("node.latency", 15*time.Nanosecond, "name", "test")
+	stats.ExpectTiming("node.latency.p50", 5*time.Nanosecond, "name", "test")
+	stats.ExpectTiming("node.latency.p95", 10*time.Nanosecond, "name", "test")
+	stats.ExpectTiming("node.latency.p99", 10*time.Nanosecond, "name", "test")
+	stats.ExpectTiming("node.latency.max", time.Microsecond, "name", "test")
+	stats.ExpectInc("node.throughput", int64(100), "name", "test")
+	stats.ExpectGauge("monitor.back-pressure", mocks.Anything)
+	stats.ExpectTiming("monitor.overhead", mocks.Anything)
+
+	mon := streams.NewMonitor(stats, time.Hour)
+
+	for i := 0; i < 94; i++ {
+		mon.Processed("test", 5*time.Nanosecond, -1)
+	}
+	for i := 0; i < 5; i++ {
+		mon.Processed("test", 10*time.Nanosecond, -1)
+	}
+	mon.Processed("test", time.Microsecond, -1)
+
+	_ = mon.Close()
+
+	stats.AssertExpectations()
+}
+
+func TestMonitor_Failed(t *testing.T) {
+	stats := mocks.NewStats(t)
+	stats.ExpectInc("node.errors", int64(2), "name", "test")
+	stats.ExpectGauge("monitor.back-pressure", mocks.Anything)
+	stats.ExpectTiming("monitor.overhead", mocks.Anything)
+
+	mon := streams.NewMonitor(stats, time.Hour)
+
+	mon.Failed("test")
+	mon.Failed("test")
+
+	_ = mon.Close()
+
+	stats.AssertExpectations()
+}
+
 func TestMonitor_Committed(t *testing.T) {
 	stat := new(MockStats)
 	stat.On("Inc", "commit.commits", int64(1), mock.Anything)
 	stat.On("Timing", "commit.latency", time.Second, mock.Anything)
 	stat.On("Gauge", "monitor.back-pressure", mock.Anything, mock.Anything)
+	stat.On("Timing", "monitor.overhead", mock.Anything, mock.Anything)
 
 	mon := streams.NewMonitor(stat, time.Microsecond)
 
@@ -51,6 +101,117 @@ func TestMonitor_Committed(t *testing.T) {
 	stat.AssertExpectations(t)
 }
 
+func TestMonitor_ProcessedConcurrently(t *testing.T) {
+	stats := newRecordingStats()
+	mon := streams.NewMonitor(stats, time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				mon.Processed("test", time.Duration(j), -1)
+				mon.Failed("test")
+			}
+		}()
+	}
+	wg.Wait()
+
+	_ = mon.Close()
+
+	assert.Equal(t, int64(8000), stats.count("node.throughput"))
+	assert.Equal(t, int64(8000), stats.count("node.errors"))
+}
+
+func TestMonitor_DoesNotBlockOnSlowStats(t *testing.T) {
+	stats := newRecordingStats()
+	stats.block()
+	mon := streams.NewMonitor(stats, time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 100; i++ {
+			mon.Processed("test", time.Millisecond, -1)
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Processed blocked on the stats")
+	}
+
+	stats.unblock()
+	_ = mon.Close()
+
+	assert.True(t, stats.count("monitor.dropped") > 0)
+	assert.Equal(t, int64(100), stats.count("node.throughput")+stats.count("monitor.dropped"))
+}
+
+func TestMonitor_ProcessedAfterClose(t *testing.T) {
+	mon := streams.NewMonitor(newRecordingStats(), time.Hour)
+	_ = mon.Close()
+
+	assert.NotPanics(t, func() {
+		mon.Processed("test", time.Second, -1)
+	})
+}
+
+// recordingStats records the counts, and can block until released.
+type recordingStats struct {
+	gate chan struct{}
+
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func newRecordingStats() *recordingStats {
+	gate := make(chan struct{})
+	close(gate)
+
+	return &recordingStats{
+		gate:   gate,
+		counts: map[string]int64{},
+	}
+}
+
+func (s *recordingStats) block() {
+	s.gate = make(chan struct{})
+}
+
+func (s *recordingStats) unblock() {
+	close(s.gate)
+}
+
+func (s *recordingStats) count(name string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.counts[name]
+}
+
+func (s *recordingStats) Inc(name string, value int64, tags ...interface{}) {
+	<-s.gate
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counts[name] += value
+}
+
+func (s *recordingStats) Gauge(name string, value float64, tags ...interface{}) {
+	<-s.gate
+}
+
+func (s *recordingStats) Timing(name string, value time.Duration, tags ...interface{}) {
+	<-s.gate
+}
+
 type MockStats struct {
 	mock.Mock
 }
@@ -1,6 +1,7 @@
 package streams
 
 import (
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/xerrors"
@@ -26,7 +27,58 @@ type Pipe interface {
 	Commit(Message) error
 }
 
+// BatchPipe represents a pipe that can move batches of messages.
+type BatchPipe interface {
+	// MarkBatch indicates that the messages have been delt with.
+	MarkBatch([]Message) error
+	// ForwardBatch queues the messages with all processor children in the topology.
+	ForwardBatch([]Message) error
+}
+
+// ForwardBatch queues the messages with all processor children in the topology.
+//
+// If the pipe is not a BatchPipe, the messages are forwarded one by one.
+func ForwardBatch(pipe Pipe, msgs []Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	if bp, ok := pipe.(BatchPipe); ok {
+		return bp.ForwardBatch(msgs)
+	}
+
+	for _, msg := range msgs {
+		if err := pipe.Forward(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MarkBatch indicates that the messages have been delt with.
+//
+// If the pipe is not a BatchPipe, the messages are marked one by one.
+func MarkBatch(pipe Pipe, msgs []Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	if bp, ok := pipe.(BatchPipe); ok {
+		return bp.MarkBatch(msgs)
+	}
+
+	for _, msg := range msgs {
+		if err := pipe.Mark(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 var _ = (TimedPipe)(&processorPipe{})
+var _ = (BatchPipe)(&processorPipe{})
 
 // processorPipe represents the pipe for processors.
 type processorPipe struct {
@@ -35,7 +87,9 @@ type processorPipe struct {
 	proc       Processor
 	children   []Pump
 
-	duration time.Duration
+	// duration is accessed atomically, as the pipe
+	// may be used by several pump workers at once.
+	duration int64
 }
 
 // NewPipe create a new processorPipe instance.
@@ -50,12 +104,12 @@ func NewPipe(store Metastore, supervisor Supervisor, proc Processor, children []
 
 // Reset resets the accumulative pipe duration.
 func (p *processorPipe) Reset() {
-	p.duration = 0
+	atomic.StoreInt64(&p.duration, 0)
 }
 
 // Duration returns the accumulative pipe duration.
 func (p *processorPipe) Duration() time.Duration {
-	return p.duration
+	return time.Duration(atomic.LoadInt64(&p.duration))
 }
 
 // Mark indicates that the message has been delt with
@@ -75,6 +129,23 @@ func (p *processorPipe) Forward(msg Message) error {
 
 	for _, child := range p.children {
 		if err := child.Accept(msg); err != nil {
+			return forwarded(err)
+		}
+	}
+
+	p.time(start)
+
+	return nil
+}
+
+// MarkBatch indicates that the messages have been delt with.
+//
+// The metadata of every message is marked, as the messages may originate from different sources.
+func (p *processorPipe) MarkBatch(msgs []Message) error {
+	start := nanotime()
+
+	for _, msg := range msgs {
+		if err := p.store.Mark(p.proc, msg.source, msg.metadata); err != nil {
 			return err
 		}
 	}
@@ -84,6 +155,21 @@ func (p *processorPipe) Forward(msg Message) error {
 	return nil
 }
 
+// ForwardBatch queues the messages to all processor children in the topology.
+func (p *processorPipe) ForwardBatch(msgs []Message) error {
+	start := nanotime()
+
+	for _, child := range p.children {
+		if err := acceptBatch(child, msgs); err != nil {
+			return forwarded(err)
+		}
+	}
+
+	p.time(start)
+
+	return nil
+}
+
 // Forward queues the data to the the given processor(inner) child in the topology.
 func (p *processorPipe) ForwardToChild(msg Message, index int) error {
 	start := nanotime()
@@ -97,7 +183,7 @@ func (p *processorPipe) ForwardToChild(msg Message, index int) error {
 
 	p.time(start)
 
-	return err
+	return forwarded(err)
 }
 
 // Commit commits the current state in the sources.
@@ -117,5 +203,57 @@ func (p *processorPipe) Commit(msg Message) error {
 
 // time adds the duration of the function to the pipe accumulative duration.
 func (p *processorPipe) time(t int64) {
-	p.duration += time.Duration(nanotime() - t) //time.Since(t)
+	atomic.AddInt64(&p.duration, nanotime()-t) //time.Since(t)
+}
+
+// forwardError represents an error returned by a child pump.
+//
+// The error was raised, and reported, by a node down the topology, so
+// the nodes it is returned through must not report it again.
+type forwardError struct {
+	err error
+}
+
+// Error returns the error message.
+func (e *forwardError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *forwardError) Unwrap() error {
+	return e.err
+}
+
+// forwarded marks the error returned by a child pump as forwarded.
+func forwarded(err error) error {
+	if err == nil || isForwarded(err) {
+		return err
+	}
+
+	return &forwardError{err: err}
+}
+
+// isForwarded determines if the error was returned by a child pump.
+func isForwarded(err error) bool {
+	var fe *forwardError
+	return xerrors.As(err, &fe)
+}
+
+// nodeError returns the error if it was raised by the node itself,
+// or nil if it was returned by a child pump.
+func nodeError(err error) error {
+	if isForwarded(err) {
+		return nil
+	}
+
+	return err
+}
+
+// unwrapForwarded returns the error raised down the topology.
+func unwrapForwarded(err error) error {
+	if fe, ok := err.(*forwardError); ok {
+		return fe.err
+	}
+
+	return err
 }
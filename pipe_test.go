@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/mocks"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -98,6 +99,81 @@ func TestProcessorPipe_ForwardError(t *testing.T) {
 	child1.AssertExpectations(t)
 }
 
+func TestProcessorPipe_ForwardBatch(t *testing.T) {
+	store := new(MockMetastore)
+	supervisor := new(MockSupervisor)
+	msgs := []streams.Message{streams.NewMessage("test", 1), streams.NewMessage("test", 2)}
+	child1 := new(MockBatchPump)
+	child1.On("AcceptBatch", msgs).Return(nil)
+	child2 := new(MockPump)
+	child2.On("Accept", msgs[0]).Return(nil)
+	child2.On("Accept", msgs[1]).Return(nil)
+	pipe := streams.NewPipe(store, supervisor, nil, []streams.Pump{child1, child2})
+
+	err := streams.ForwardBatch(pipe, msgs)
+
+	assert.NoError(t, err)
+	child1.AssertExpectations(t)
+	child2.AssertExpectations(t)
+}
+
+func TestProcessorPipe_ForwardBatchError(t *testing.T) {
+	store := new(MockMetastore)
+	supervisor := new(MockSupervisor)
+	msgs := []streams.Message{streams.NewMessage("test", 1)}
+	child1 := new(MockBatchPump)
+	child1.On("AcceptBatch", msgs).Return(errors.New("test"))
+	pipe := streams.NewPipe(store, supervisor, nil, []streams.Pump{child1})
+
+	err := streams.ForwardBatch(pipe, msgs)
+
+	assert.Error(t, err)
+}
+
+func TestProcessorPipe_MarkBatch(t *testing.T) {
+	proc := new(MockProcessor)
+	src1 := new(MockSource)
+	src2 := new(MockSource)
+	store := new(MockMetastore)
+	supervisor := new(MockSupervisor)
+	meta1 := new(MockMetadata)
+	meta2 := new(MockMetadata)
+	store.On("Mark", proc, src1, meta1).Return(nil)
+	store.On("Mark", proc, src2, meta2).Return(nil)
+	msgs := []streams.Message{
+		streams.NewMessage("test", 1).WithMetadata(src1, meta1),
+		streams.NewMessage("test", 2).WithMetadata(src2, meta2),
+	}
+	pipe := streams.NewPipe(store, supervisor, proc, []streams.Pump{})
+
+	err := streams.MarkBatch(pipe, msgs)
+
+	assert.NoError(t, err)
+	store.AssertExpectations(t)
+}
+
+func TestMarkBatch_FallsBackToMark(t *testing.T) {
+	pipe := mocks.NewPipe(t)
+	pipe.ExpectMark("test", 1)
+	pipe.ExpectMark("test", 2)
+
+	err := streams.MarkBatch(pipe, []streams.Message{streams.NewMessage("test", 1), streams.NewMessage("test", 2)})
+
+	assert.NoError(t, err)
+	pipe.AssertExpectations()
+}
+
+func TestForwardBatch_FallsBackToForward(t *testing.T) {
+	pipe := mocks.NewPipe(t)
+	pipe.ExpectForward("test", 1)
+	pipe.ExpectForward("test", 2)
+
+	err := streams.ForwardBatch(pipe, []streams.Message{streams.NewMessage("test", 1), streams.NewMessage("test", 2)})
+
+	assert.NoError(t, err)
+	pipe.AssertExpectations()
+}
+
 func TestProcessorPipe_ForwardToChild(t *testing.T) {
 	store := new(MockMetastore)
 	supervisor := new(MockSupervisor)
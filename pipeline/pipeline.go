@@ -0,0 +1,361 @@
+// Package pipeline builds stream topologies from declarative YAML or JSON definitions.
+//
+// Sources, processors and sinks are referenced by the name of a factory
+// registered in a Registry, and are given the parameters of the definition:
+//
+//	sources:
+//	  - name: events
+//	    type: kafka
+//	    params:
+//	      topic: events
+//	processors:
+//	  - name: parse
+//	    type: json
+//	    from: [events]
+//	    workers: 4
+//	sinks:
+//	  - name: store
+//	    type: sql
+//	    from: [parse]
+//
+// Nodes must be defined after the nodes they consume from. A processor of the
+// built-in type "merge" merges the streams of all the nodes it consumes from,
+// and takes no params, workers or buffer size.
+package pipeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/rafalmnich/streams/v6"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+)
+
+// MergeType is the type of the built-in processor merging streams.
+const MergeType = "merge"
+
+// Params represents the parameters of a node definition.
+type Params map[string]interface{}
+
+// Decode decodes the parameters into the value pointed to by v,
+// following the rules of encoding/json.
+func (p Params) Decode(v interface{}) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// SourceFactory creates a Source from the parameters of a definition.
+type SourceFactory func(Params) (streams.Source, error)
+
+// ProcessorFactory creates a Processor from the parameters of a definition.
+type ProcessorFactory func(Params) (streams.Processor, error)
+
+// Registry represents a registry of named source and processor factories.
+type Registry struct {
+	sources    map[string]SourceFactory
+	processors map[string]ProcessorFactory
+}
+
+// NewRegistry creates a new Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		sources:    map[string]SourceFactory{},
+		processors: map[string]ProcessorFactory{},
+	}
+}
+
+// RegisterSource registers a source factory under the given type name.
+func (r *Registry) RegisterSource(typ string, fn SourceFactory) {
+	r.sources[typ] = fn
+}
+
+// RegisterProcessor registers a processor factory under the given type name.
+//
+// Processor factories are used for both processors and sinks.
+func (r *Registry) RegisterProcessor(typ string, fn ProcessorFactory) {
+	r.processors[typ] = fn
+}
+
+// NodeDefinition represents the definition of a node in a pipeline.
+type NodeDefinition struct {
+	// Name is the name of the node.
+	Name string `yaml:"name"`
+	// Type is the name of the factory creating the source or processor.
+	Type string `yaml:"type"`
+	// From are the names of the nodes consumed from. Sources do not consume from any node.
+	From []string `yaml:"from"`
+	// Params are the parameters passed to the factory.
+	Params Params `yaml:"params"`
+	// Workers is the number of workers of the asynchronous pump of a processor.
+	Workers int `yaml:"workers"`
+	// BufferSize is the buffer size of the asynchronous pump of a processor.
+	BufferSize int `yaml:"bufferSize"`
+}
+
+// Definition represents the definition of a pipeline.
+type Definition struct {
+	Sources    []NodeDefinition `yaml:"sources"`
+	Processors []NodeDefinition `yaml:"processors"`
+	Sinks      []NodeDefinition `yaml:"sinks"`
+}
+
+// Parse parses a YAML or JSON pipeline definition.
+//
+// Unknown fields in the definition are reported as errors.
+func Parse(data []byte) (*Definition, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	var def Definition
+	if err := dec.Decode(&def); err != nil {
+		return nil, xerrors.Errorf("pipeline: %w", err)
+	}
+
+	return &def, nil
+}
+
+// Load parses a YAML or JSON pipeline definition and builds its Topology.
+func Load(r *Registry, data []byte) (*streams.Topology, []error) {
+	def, err := Parse(data)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return r.Build(def)
+}
+
+// PathError represents an error in the definition at the given document path.
+type PathError struct {
+	Path string
+	Err  error
+}
+
+// Error returns the error message.
+func (e *PathError) Error() string {
+	return "pipeline: " + e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// Build builds the Topology of the definition.
+//
+// If the definition is invalid, the errors point at the offending document
+// path, the sources and processors already created are closed and no
+// topology is returned. Otherwise the topology is built and
+// the errors of its inspections are returned.
+func (r *Registry) Build(def *Definition) (*streams.Topology, []error) {
+	b := &builder{
+		reg:     r,
+		sb:      streams.NewStreamBuilder(),
+		streams: map[string]*streams.Stream{},
+		sinks:   map[string]bool{},
+	}
+
+	for i, n := range def.Sources {
+		b.addSource(nodePath("sources", i), n)
+	}
+	for i, n := range def.Processors {
+		b.addProcessor(nodePath("processors", i), n, false)
+	}
+	for i, n := range def.Sinks {
+		b.addProcessor(nodePath("sinks", i), n, true)
+	}
+
+	if len(b.errs) > 0 {
+		b.close()
+		return nil, b.errs
+	}
+
+	return b.sb.Build()
+}
+
+// node represents a source or processor created by a factory.
+type node struct {
+	path string
+	c    io.Closer
+}
+
+type builder struct {
+	reg     *Registry
+	sb      *streams.StreamBuilder
+	streams map[string]*streams.Stream
+	sinks   map[string]bool
+	nodes   []node
+	errs    []error
+}
+
+func (b *builder) error(path string, err error) {
+	b.errs = append(b.errs, &PathError{Path: path, Err: err})
+}
+
+// close closes the created nodes in the reversed order, collecting the errors.
+func (b *builder) close() {
+	for i := len(b.nodes) - 1; i >= 0; i-- {
+		n := b.nodes[i]
+		if err := n.c.Close(); err != nil {
+			b.error(n.path, xerrors.Errorf("close: %w", err))
+		}
+	}
+}
+
+// checkName checks the node name is set and unique.
+func (b *builder) checkName(path string, n NodeDefinition) bool {
+	if n.Name == "" {
+		b.error(path+".name", errors.New("name is required"))
+		return false
+	}
+
+	if _, ok := b.streams[n.Name]; ok || b.sinks[n.Name] {
+		b.error(path+".name", xerrors.Errorf("duplicate node name %q", n.Name))
+		return false
+	}
+
+	return true
+}
+
+func (b *builder) addSource(path string, n NodeDefinition) {
+	if !b.checkName(path, n) {
+		return
+	}
+
+	if len(n.From) > 0 {
+		b.error(path+".from", errors.New("sources cannot consume from other nodes"))
+		return
+	}
+
+	fn, ok := b.reg.sources[n.Type]
+	if !ok {
+		b.error(path+".type", xerrors.Errorf("unknown source type %q", n.Type))
+		return
+	}
+
+	src, err := fn(n.Params)
+	if err != nil {
+		b.error(path+".params", err)
+		return
+	}
+	b.nodes = append(b.nodes, node{path: path, c: src})
+
+	b.streams[n.Name] = b.sb.Source(n.Name, src)
+}
+
+func (b *builder) addProcessor(path string, n NodeDefinition, sink bool) {
+	if !b.checkName(path, n) {
+		return
+	}
+
+	parents, ok := b.parents(path, n)
+	if !ok {
+		return
+	}
+
+	var stream *streams.Stream
+	if n.Type == MergeType {
+		if !b.checkMerge(path, n) {
+			return
+		}
+
+		stream = parents[0].Merge(n.Name, parents[1:]...)
+	} else {
+		if len(parents) != 1 {
+			b.error(path+".from", errors.New("exactly one node is required"))
+			return
+		}
+
+		fn, ok := b.reg.processors[n.Type]
+		if !ok {
+			b.error(path+".type", xerrors.Errorf("unknown processor type %q", n.Type))
+			return
+		}
+
+		p, err := fn(n.Params)
+		if err != nil {
+			b.error(path+".params", err)
+			return
+		}
+		b.nodes = append(b.nodes, node{path: path, c: p})
+
+		stream = parents[0].Process(n.Name, p, pumpOpts(n)...)
+	}
+
+	if sink {
+		b.sinks[n.Name] = true
+		return
+	}
+	b.streams[n.Name] = stream
+}
+
+// checkMerge checks no settings unsupported by merge nodes are set.
+func (b *builder) checkMerge(path string, n NodeDefinition) bool {
+	ok := true
+	if n.Workers != 0 {
+		b.error(path+".workers", errors.New("merge nodes do not support workers"))
+		ok = false
+	}
+	if n.BufferSize != 0 {
+		b.error(path+".bufferSize", errors.New("merge nodes do not support a buffer size"))
+		ok = false
+	}
+	if len(n.Params) > 0 {
+		b.error(path+".params", errors.New("merge nodes do not support params"))
+		ok = false
+	}
+
+	return ok
+}
+
+// parents resolves the streams the node consumes from.
+func (b *builder) parents(path string, n NodeDefinition) ([]*streams.Stream, bool) {
+	if len(n.From) == 0 {
+		b.error(path+".from", errors.New("at least one node is required"))
+		return nil, false
+	}
+
+	parents := make([]*streams.Stream, 0, len(n.From))
+	for i, name := range n.From {
+		p := path + ".from[" + strconv.Itoa(i) + "]"
+
+		if b.sinks[name] {
+			b.error(p, xerrors.Errorf("cannot consume from sink %q", name))
+			return nil, false
+		}
+
+		stream, ok := b.streams[name]
+		if !ok {
+			b.error(p, xerrors.Errorf("unknown node %q", name))
+			return nil, false
+		}
+
+		parents = append(parents, stream)
+	}
+
+	return parents, true
+}
+
+// pumpOpts returns the pump options of the node.
+func pumpOpts(n NodeDefinition) []streams.PumpOptFunc {
+	var opts []streams.PumpOptFunc
+	if n.Workers > 0 {
+		opts = append(opts, streams.WithWorkers(n.Workers))
+	}
+	if n.BufferSize > 0 {
+		opts = append(opts, streams.WithBufferSize(n.BufferSize))
+	}
+
+	return opts
+}
+
+func nodePath(section string, i int) string {
+	return section + "[" + strconv.Itoa(i) + "]"
+}
@@ -0,0 +1,272 @@
+package pipeline_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/mocks"
+	"github.com/rafalmnich/streams/v6/pipeline"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRegistry() *pipeline.Registry {
+	r := pipeline.NewRegistry()
+	r.RegisterSource("fake", func(params pipeline.Params) (streams.Source, error) {
+		return &fakeSource{params: params}, nil
+	})
+	r.RegisterSource("broken", func(pipeline.Params) (streams.Source, error) {
+		return nil, errors.New("test error")
+	})
+	r.RegisterProcessor("map", func(pipeline.Params) (streams.Processor, error) {
+		return streams.NewMapProcessor(streams.MapperFunc(func(msg streams.Message) (streams.Message, error) {
+			return msg, nil
+		})), nil
+	})
+	r.RegisterProcessor("print", func(params pipeline.Params) (streams.Processor, error) {
+		return streams.NewPrintProcessor(), nil
+	})
+
+	return r
+}
+
+func TestParams_Decode(t *testing.T) {
+	params := pipeline.Params{"topic": "test", "partitions": 3}
+
+	var v struct {
+		Topic      string `json:"topic"`
+		Partitions int    `json:"partitions"`
+	}
+	err := params.Decode(&v)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "test", v.Topic)
+	assert.Equal(t, 3, v.Partitions)
+}
+
+func TestParse(t *testing.T) {
+	doc := `
+sources:
+  - name: src
+    type: fake
+    params:
+      topic: test
+processors:
+  - name: map
+    type: map
+    from: [src]
+    workers: 2
+    bufferSize: 10
+sinks:
+  - name: sink
+    type: print
+    from: [map]
+`
+
+	def, err := pipeline.Parse([]byte(doc))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &pipeline.Definition{
+		Sources: []pipeline.NodeDefinition{
+			{Name: "src", Type: "fake", Params: pipeline.Params{"topic": "test"}},
+		},
+		Processors: []pipeline.NodeDefinition{
+			{Name: "map", Type: "map", From: []string{"src"}, Workers: 2, BufferSize: 10},
+		},
+		Sinks: []pipeline.NodeDefinition{
+			{Name: "sink", Type: "print", From: []string{"map"}},
+		},
+	}, def)
+}
+
+func TestParse_UnknownField(t *testing.T) {
+	_, err := pipeline.Parse([]byte("sources:\n  - name: src\n    typo: fake\n"))
+
+	assert.Error(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	doc := `
+sources:
+  - {name: src1, type: fake}
+  - {name: src2, type: fake}
+processors:
+  - {name: merge, type: merge, from: [src1, src2]}
+  - {name: map, type: map, from: [merge]}
+sinks:
+  - {name: sink, type: print, from: [map]}
+`
+
+	tp, errs := pipeline.Load(newRegistry(), []byte(doc))
+
+	assert.Len(t, errs, 0)
+	desc := tp.Describe()
+	assert.Equal(t, []streams.NodeDescription{
+		{Name: "src1", Type: "*pipeline_test.fakeSource", Children: []string{"merge"}},
+		{Name: "src2", Type: "*pipeline_test.fakeSource", Children: []string{"merge"}},
+	}, desc.Sources)
+	assert.Equal(t, []streams.NodeDescription{
+		{Name: "merge", Type: "*streams.MergeProcessor", Children: []string{"map"}},
+		{Name: "map", Type: "*streams.MapProcessor", Children: []string{"sink"}},
+		{Name: "sink", Type: "*streams.PrintProcessor", Children: []string{}},
+	}, desc.Processors)
+}
+
+func TestLoad_JSON(t *testing.T) {
+	doc := `{
+		"sources": [{"name": "src", "type": "fake"}],
+		"sinks": [{"name": "sink", "type": "print", "from": ["src"]}]
+	}`
+
+	tp, errs := pipeline.Load(newRegistry(), []byte(doc))
+
+	assert.Len(t, errs, 0)
+	assert.Len(t, tp.Processors(), 1)
+}
+
+func TestLoad_ParseError(t *testing.T) {
+	tp, errs := pipeline.Load(newRegistry(), []byte("sources: ["))
+
+	assert.Nil(t, tp)
+	assert.Len(t, errs, 1)
+}
+
+func TestLoad_ValidationErrors(t *testing.T) {
+	doc := `
+sources:
+  - {name: src, type: fake}
+  - {type: fake}
+  - {name: src, type: fake}
+  - {name: unknown, type: nope}
+  - {name: broken, type: broken}
+  - {name: consumer, type: fake, from: [src]}
+processors:
+  - {name: orphan, type: map}
+  - {name: missing, type: map, from: [nope]}
+  - {name: many, type: map, from: [src, src]}
+  - {name: bad-type, type: nope, from: [src]}
+sinks:
+  - {name: sink, type: print, from: [src]}
+  - {name: after-sink, type: print, from: [sink]}
+`
+
+	tp, errs := pipeline.Load(newRegistry(), []byte(doc))
+
+	assert.Nil(t, tp)
+	var msgs []string
+	for _, err := range errs {
+		assert.IsType(t, &pipeline.PathError{}, err)
+		msgs = append(msgs, err.Error())
+	}
+	assert.Equal(t, []string{
+		"pipeline: sources[1].name: name is required",
+		`pipeline: sources[2].name: duplicate node name "src"`,
+		`pipeline: sources[3].type: unknown source type "nope"`,
+		"pipeline: sources[4].params: test error",
+		"pipeline: sources[5].from: sources cannot consume from other nodes",
+		"pipeline: processors[0].from: at least one node is required",
+		`pipeline: processors[1].from[0]: unknown node "nope"`,
+		"pipeline: processors[2].from: exactly one node is required",
+		`pipeline: processors[3].type: unknown processor type "nope"`,
+		`pipeline: sinks[1].from[0]: cannot consume from sink "sink"`,
+	}, msgs)
+}
+
+func TestLoad_MergeSettings(t *testing.T) {
+	doc := `
+sources:
+  - {name: src1, type: fake}
+  - {name: src2, type: fake}
+processors:
+  - {name: merged, type: merge, from: [src1, src2], workers: 4, bufferSize: 10, params: {foo: bar}}
+`
+
+	tp, errs := pipeline.Load(newRegistry(), []byte(doc))
+
+	assert.Nil(t, tp)
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	assert.Equal(t, []string{
+		"pipeline: processors[0].workers: merge nodes do not support workers",
+		"pipeline: processors[0].bufferSize: merge nodes do not support a buffer size",
+		"pipeline: processors[0].params: merge nodes do not support params",
+	}, msgs)
+}
+
+func TestLoad_ValidationErrorsClosesNodes(t *testing.T) {
+	src := &fakeSource{}
+	broken := &fakeSource{closeErr: errors.New("test error")}
+	proc := mocks.NewProcessor(t)
+	proc.ExpectClose()
+	r := newRegistry()
+	r.RegisterSource("tracked", func(pipeline.Params) (streams.Source, error) {
+		return src, nil
+	})
+	r.RegisterSource("unclosable", func(pipeline.Params) (streams.Source, error) {
+		return broken, nil
+	})
+	r.RegisterProcessor("tracked", func(pipeline.Params) (streams.Processor, error) {
+		return proc, nil
+	})
+	doc := `
+sources:
+  - {name: src, type: tracked}
+  - {name: broken, type: unclosable}
+processors:
+  - {name: proc, type: tracked, from: [src]}
+  - {name: bad-type, type: nope, from: [src]}
+`
+
+	tp, errs := pipeline.Load(r, []byte(doc))
+
+	assert.Nil(t, tp)
+	assert.Len(t, errs, 2)
+	assert.EqualError(t, errs[1], "pipeline: sources[1]: close: test error")
+	assert.True(t, src.closed)
+	assert.True(t, broken.closed)
+	proc.AssertExpectations()
+}
+
+func TestLoad_RunsInspections(t *testing.T) {
+	doc := `
+sources:
+  - {name: src1, type: fake}
+  - {name: src2, type: fake}
+`
+
+	tp, errs := pipeline.Load(newRegistry(), []byte(doc))
+
+	assert.NotNil(t, tp)
+	assert.Equal(t, []error{errors.New("streams: not all sources are connected")}, errs)
+}
+
+func TestPathError_Unwrap(t *testing.T) {
+	inner := errors.New("test error")
+	err := &pipeline.PathError{Path: "sources[0]", Err: inner}
+
+	assert.True(t, errors.Is(err, inner))
+	assert.Equal(t, "pipeline: sources[0]: test error", err.Error())
+}
+
+type fakeSource struct {
+	params pipeline.Params
+
+	closed   bool
+	closeErr error
+}
+
+func (*fakeSource) Consume() (streams.Message, error) {
+	return streams.EmptyMessage, nil
+}
+
+func (*fakeSource) Commit(interface{}) error {
+	return nil
+}
+
+func (s *fakeSource) Close() error {
+	s.closed = true
+
+	return s.closeErr
+}
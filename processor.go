@@ -3,6 +3,7 @@ package streams
 import (
 	"context"
 	"fmt"
+	"sync"
 )
 
 // Committer represents a processor that can commit.
@@ -23,6 +24,19 @@ type Processor interface {
 	Close() error
 }
 
+// BatchProcessor represents a stream processor that can process Messages in batches.
+//
+// When a Processor implements BatchProcessor, the pumps use ProcessBatch
+// for batches of Messages in favour of calling Process for each Message.
+type BatchProcessor interface {
+	Processor
+
+	// ProcessBatch processes a batch of stream Messages.
+	//
+	// The batch may be shared with other processors and must not be modified.
+	ProcessBatch([]Message) error
+}
+
 // Mapper represents a message transformer.
 type Mapper interface {
 	// Map transforms a message into a new value.
@@ -147,6 +161,30 @@ func (p *FilterProcessor) Process(msg Message) error {
 	return p.pipe.Mark(msg)
 }
 
+// ProcessBatch processes a batch of stream Messages.
+func (p *FilterProcessor) ProcessBatch(msgs []Message) error {
+	var fwd, mark []Message
+	for _, msg := range msgs {
+		ok, err := p.pred.Assert(msg)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			fwd = append(fwd, msg)
+			continue
+		}
+
+		mark = append(mark, msg)
+	}
+
+	if err := ForwardBatch(p.pipe, fwd); err != nil {
+		return err
+	}
+
+	return MarkBatch(p.pipe, mark)
+}
+
 // Close closes the processor.
 func (p *FilterProcessor) Close() error {
 	return nil
@@ -219,11 +257,257 @@ func (p *MapProcessor) Process(msg Message) error {
 	return p.pipe.Forward(msg)
 }
 
+// ProcessBatch processes a batch of stream Messages.
+func (p *MapProcessor) ProcessBatch(msgs []Message) error {
+	out := make([]Message, 0, len(msgs))
+	for _, msg := range msgs {
+		msg, err := p.mapper.Map(msg)
+		if err != nil {
+			return err
+		}
+
+		out = append(out, msg)
+	}
+
+	return ForwardBatch(p.pipe, out)
+}
+
 // Close closes the processor.
 func (p *MapProcessor) Close() error {
 	return nil
 }
 
+// asyncEntry represents a Message in flight in an AsyncMapProcessor.
+type asyncEntry struct {
+	src    Source
+	meta   Metadata
+	result Message
+	done   bool
+	failed bool
+}
+
+// AsyncMapProcessor is a processor that maps a stream using a mapping function,
+// with multiple messages being mapped concurrently.
+//
+// The metadata of a message is only passed on once the message and all the
+// messages received before it from the same source have been mapped and forwarded,
+// so that no message still in flight can be committed.
+type AsyncMapProcessor struct {
+	pipe    Pipe
+	mapper  Mapper
+	ordered bool
+
+	sem chan struct{}
+	wg  sync.WaitGroup
+
+	// emitMu serialises the forwarding of the mapped messages, without
+	// blocking the mapped messages being recorded while forwarding.
+	emitMu sync.Mutex
+
+	mu     sync.Mutex
+	queues map[Source][]*asyncEntry
+	err    error
+}
+
+// NewAsyncMapProcessor creates a new AsyncMapProcessor instance that maps up to n
+// messages concurrently, forwarding the mapped messages in the order they were received.
+//
+// The mapper must be safe for concurrent use.
+func NewAsyncMapProcessor(mapper Mapper, n int) Processor {
+	return newAsyncMapProcessor(mapper, n, true)
+}
+
+// NewAsyncMapUnorderedProcessor creates a new AsyncMapProcessor instance that maps up to n
+// messages concurrently, forwarding the mapped messages as soon as they are mapped.
+//
+// The mapper must be safe for concurrent use.
+func NewAsyncMapUnorderedProcessor(mapper Mapper, n int) Processor {
+	return newAsyncMapProcessor(mapper, n, false)
+}
+
+func newAsyncMapProcessor(mapper Mapper, n int, ordered bool) *AsyncMapProcessor {
+	if n < 1 {
+		n = 1
+	}
+
+	return &AsyncMapProcessor{
+		mapper:  mapper,
+		ordered: ordered,
+		sem:     make(chan struct{}, n),
+		queues:  map[Source][]*asyncEntry{},
+	}
+}
+
+// WithPipe sets the pipe on the Processor.
+func (p *AsyncMapProcessor) WithPipe(pipe Pipe) {
+	p.pipe = pipe
+}
+
+// Process processes the stream Message.
+//
+// Process blocks while the maximum number of messages are in flight. Errors
+// of messages in flight are returned on the following call.
+func (p *AsyncMapProcessor) Process(msg Message) error {
+	if err := p.error(); err != nil {
+		return err
+	}
+
+	p.sem <- struct{}{}
+
+	// An error may have occurred while waiting for a free slot.
+	if err := p.error(); err != nil {
+		<-p.sem
+		return err
+	}
+
+	src, meta := msg.Metadata()
+	e := &asyncEntry{src: src, meta: meta}
+	key := p.queueKey(src)
+
+	p.mu.Lock()
+	p.queues[key] = append(p.queues[key], e)
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	go p.run(key, e, msg)
+
+	return nil
+}
+
+func (p *AsyncMapProcessor) run(key Source, e *asyncEntry, msg Message) {
+	defer p.wg.Done()
+
+	res, err := p.mapper.Map(msg)
+
+	p.mu.Lock()
+	e.result, e.done = res, true
+	if err != nil {
+		e.failed = true
+		p.fail(err)
+	}
+	p.mu.Unlock()
+
+	p.emitMu.Lock()
+	defer p.emitMu.Unlock()
+
+	if p.ordered {
+		p.emitOrdered(key)
+		return
+	}
+
+	p.emitUnordered(key, e)
+}
+
+// emitOrdered forwards the mapped messages at the head of the queue.
+//
+// The emit mutex must be held while calling emitOrdered.
+func (p *AsyncMapProcessor) emitOrdered(key Source) {
+	p.mu.Lock()
+	var ready []*asyncEntry
+	q := p.queues[key]
+	for len(q) > 0 && q[0].done {
+		ready = append(ready, q[0])
+		q = q[1:]
+	}
+	p.queues[key] = q
+	p.mu.Unlock()
+
+	for _, e := range ready {
+		if !e.failed && p.error() == nil {
+			p.forward(e.result, e.src, e.meta)
+		}
+		<-p.sem
+	}
+}
+
+// emitUnordered forwards the mapped message, along with the metadata
+// of the messages at the head of the queue that are done.
+//
+// The emit mutex must be held while calling emitUnordered.
+func (p *AsyncMapProcessor) emitUnordered(key Source, e *asyncEntry) {
+	var src Source
+	var meta Metadata
+
+	p.mu.Lock()
+	q := p.queues[key]
+	for len(q) > 0 && q[0].done {
+		src = q[0].src
+		meta = mergeMetadata(meta, q[0].meta)
+		q = q[1:]
+	}
+	p.queues[key] = q
+	p.mu.Unlock()
+
+	if !e.failed && p.error() == nil {
+		p.forward(e.result, src, meta)
+	}
+	<-p.sem
+}
+
+// forward forwards the mapped message with the given metadata, recording any error.
+func (p *AsyncMapProcessor) forward(msg Message, src Source, meta Metadata) {
+	if err := p.pipe.Forward(msg.WithMetadata(src, meta)); err != nil {
+		p.mu.Lock()
+		p.fail(err)
+		p.mu.Unlock()
+	}
+}
+
+// fail records the first error of the processor.
+//
+// The mutex must be held while calling fail.
+func (p *AsyncMapProcessor) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+func (p *AsyncMapProcessor) error() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.err
+}
+
+// queueKey returns the key of the queue the messages of the source are tracked in.
+//
+// Ordered processors track all messages in a single queue.
+func (p *AsyncMapProcessor) queueKey(src Source) Source {
+	if p.ordered {
+		return nil
+	}
+
+	return src
+}
+
+// drain waits for the messages in flight to be mapped and forwarded.
+func (p *AsyncMapProcessor) drain() {
+	p.wg.Wait()
+}
+
+// Close closes the processor.
+//
+// Close waits for the messages in flight, returning the
+// error of any message that could not be mapped.
+func (p *AsyncMapProcessor) Close() error {
+	p.drain()
+
+	return p.error()
+}
+
+// mergeMetadata merges the metadata into the accumulated metadata.
+func mergeMetadata(acc, meta Metadata) Metadata {
+	if acc == nil {
+		return meta
+	}
+
+	if meta == nil {
+		return acc
+	}
+
+	return meta.Merge(acc, Dupless)
+}
+
 // MergeProcessor is a processor that merges multiple streams.
 type MergeProcessor struct {
 	pipe Pipe
@@ -244,6 +528,11 @@ func (p *MergeProcessor) Process(msg Message) error {
 	return p.pipe.Forward(msg)
 }
 
+// ProcessBatch processes a batch of stream Messages.
+func (p *MergeProcessor) ProcessBatch(msgs []Message) error {
+	return ForwardBatch(p.pipe, msgs)
+}
+
 // Close closes the processor.
 func (p *MergeProcessor) Close() error {
 	return nil
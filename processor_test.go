@@ -3,6 +3,7 @@ package streams_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/rafalmnich/streams/v6"
 	"github.com/rafalmnich/streams/v6/mocks"
@@ -83,6 +84,23 @@ func TestFilterProcessor_Process(t *testing.T) {
 	pipe.AssertExpectations()
 }
 
+func TestFilterProcessor_ProcessBatch(t *testing.T) {
+	pred := streams.PredicateFunc(func(msg streams.Message) (bool, error) {
+		_, ok := msg.Key.(string)
+		return ok, nil
+	})
+	pipe := mocks.NewPipe(t)
+	pipe.ExpectForward("test", "test")
+	pipe.ExpectMark(1, 1)
+	p := streams.NewFilterProcessor(pred)
+	p.WithPipe(pipe)
+
+	err := p.(streams.BatchProcessor).ProcessBatch([]streams.Message{streams.NewMessage(1, 1), streams.NewMessage("test", "test")})
+
+	assert.NoError(t, err)
+	pipe.AssertExpectations()
+}
+
 func TestFilterProcessor_ProcessWithError(t *testing.T) {
 	errPred := streams.PredicateFunc(func(msg streams.Message) (bool, error) {
 		return true, errors.New("test")
@@ -118,6 +136,38 @@ func TestMapProcessor_Process(t *testing.T) {
 	pipe.AssertExpectations()
 }
 
+func TestMapProcessor_ProcessBatch(t *testing.T) {
+	mapper := streams.MapperFunc(func(msg streams.Message) (streams.Message, error) {
+		msg.Value = msg.Value.(int) * 2
+		return msg, nil
+	})
+	pipe := mocks.NewPipe(t)
+	pipe.ExpectForward("test", 2)
+	pipe.ExpectForward("test", 4)
+	p := streams.NewMapProcessor(mapper)
+	p.WithPipe(pipe)
+	in := []streams.Message{streams.NewMessage("test", 1), streams.NewMessage("test", 2)}
+
+	err := p.(streams.BatchProcessor).ProcessBatch(in)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, in[0].Value, "Expected the input batch not to be modified")
+	pipe.AssertExpectations()
+}
+
+func TestMapProcessor_ProcessBatchWithError(t *testing.T) {
+	mapper := streams.MapperFunc(func(msg streams.Message) (streams.Message, error) {
+		return streams.Message{}, errors.New("test")
+	})
+	pipe := mocks.NewPipe(t)
+	p := streams.NewMapProcessor(mapper)
+	p.WithPipe(pipe)
+
+	err := p.(streams.BatchProcessor).ProcessBatch([]streams.Message{streams.NewMessage("test", 1)})
+
+	assert.Error(t, err)
+}
+
 func TestMapProcessor_ProcessWithError(t *testing.T) {
 	mapper := streams.MapperFunc(func(msg streams.Message) (streams.Message, error) {
 		return streams.EmptyMessage, errors.New("test")
@@ -197,6 +247,200 @@ func TestFlatMapProcessor_Close(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestAsyncMapProcessor_Process(t *testing.T) {
+	mapper := streams.MapperFunc(func(msg streams.Message) (streams.Message, error) {
+		time.Sleep(time.Duration(5-msg.Value.(int)) * time.Millisecond)
+
+		return streams.NewMessage(msg.Key, msg.Value.(int)*10), nil
+	})
+	src := &fakeSource{}
+	pipe := mocks.NewPipe(t)
+	for i := 0; i < 5; i++ {
+		pipe.ExpectForward("test", i*10)
+	}
+	p := streams.NewAsyncMapProcessor(mapper, 5)
+	p.WithPipe(pipe)
+
+	for i := 0; i < 5; i++ {
+		err := p.Process(streams.NewMessage("test", i).WithMetadata(src, offsetMetadata(i)))
+		assert.NoError(t, err)
+	}
+	err := p.Close()
+
+	assert.NoError(t, err)
+	pipe.AssertExpectations()
+	for i, msg := range pipe.Messages() {
+		_, meta := msg.Msg.Metadata()
+		assert.Equal(t, offsetMetadata(i), meta)
+	}
+}
+
+func TestAsyncMapProcessor_ProcessForwardsEmptyMessages(t *testing.T) {
+	mapper := streams.MapperFunc(func(msg streams.Message) (streams.Message, error) {
+		return streams.EmptyMessage, nil
+	})
+	pipe := mocks.NewPipe(t)
+	pipe.ExpectForward(nil, nil)
+	p := streams.NewAsyncMapProcessor(mapper, 2)
+	p.WithPipe(pipe)
+
+	err := p.Process(streams.NewMessage("test", "test"))
+	assert.NoError(t, err)
+	err = p.Close()
+
+	assert.NoError(t, err)
+	pipe.AssertExpectations()
+}
+
+func TestAsyncMapProcessor_ProcessWithError(t *testing.T) {
+	mapper := streams.MapperFunc(func(msg streams.Message) (streams.Message, error) {
+		return streams.EmptyMessage, errors.New("test")
+	})
+	pipe := mocks.NewPipe(t)
+	p := streams.NewAsyncMapProcessor(mapper, 1)
+	p.WithPipe(pipe)
+
+	_ = p.Process(streams.NewMessage("test", "test"))
+	err := p.Process(streams.NewMessage("test", "test"))
+
+	assert.Error(t, err)
+	assert.Error(t, p.Close())
+}
+
+func TestAsyncMapProcessor_ProcessRemovesFailedMessages(t *testing.T) {
+	release := make(chan struct{})
+	mapper := streams.MapperFunc(func(msg streams.Message) (streams.Message, error) {
+		if msg.Value.(int) == 0 {
+			<-release
+			return streams.EmptyMessage, errors.New("test")
+		}
+
+		return msg, nil
+	})
+	pipe := mocks.NewPipe(t)
+	p := streams.NewAsyncMapProcessor(mapper, 2)
+	p.WithPipe(pipe)
+
+	_ = p.Process(streams.NewMessage("test", 0))
+	_ = p.Process(streams.NewMessage("test", 1))
+	close(release)
+
+	// Both slots are released once the failed head is removed,
+	// so the next call returns the error instead of blocking.
+	done := make(chan error)
+	go func() {
+		_ = p.Process(streams.NewMessage("test", 2))
+		done <- p.Process(streams.NewMessage("test", 3))
+	}()
+
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Process blocked behind the failed message")
+	}
+	assert.Error(t, p.Close())
+	pipe.AssertExpectations()
+}
+
+func TestAsyncMapProcessor_ProcessDoesNotBlockOnSlowForward(t *testing.T) {
+	release := make(chan struct{})
+	mapper := streams.MapperFunc(func(msg streams.Message) (streams.Message, error) {
+		return msg, nil
+	})
+	pipe := &blockingPipe{release: release, forwarding: make(chan struct{}, 1)}
+	p := streams.NewAsyncMapProcessor(mapper, 2)
+	p.WithPipe(pipe)
+
+	_ = p.Process(streams.NewMessage("test", 0))
+	<-pipe.forwarding
+
+	done := make(chan error)
+	go func() {
+		done <- p.Process(streams.NewMessage("test", 1))
+	}()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Process blocked on the slow forward")
+	}
+	close(release)
+	assert.NoError(t, p.Close())
+}
+
+type blockingPipe struct {
+	release    chan struct{}
+	forwarding chan struct{}
+}
+
+func (p *blockingPipe) Mark(streams.Message) error { return nil }
+
+func (p *blockingPipe) Forward(streams.Message) error {
+	select {
+	case p.forwarding <- struct{}{}:
+	default:
+	}
+	<-p.release
+
+	return nil
+}
+
+func (p *blockingPipe) ForwardToChild(streams.Message, int) error { return nil }
+
+func (p *blockingPipe) Commit(streams.Message) error { return nil }
+
+func TestAsyncMapUnorderedProcessor_Process(t *testing.T) {
+	release := make(chan struct{})
+	mapper := streams.MapperFunc(func(msg streams.Message) (streams.Message, error) {
+		if msg.Value.(int) == 0 {
+			<-release
+		}
+
+		return msg, nil
+	})
+	src := &fakeSource{}
+	pipe := mocks.NewPipe(t)
+	pipe.ExpectForward("test", mocks.Anything)
+	pipe.ExpectForward("test", mocks.Anything)
+	pipe.ExpectForward("test", 0)
+	p := streams.NewAsyncMapUnorderedProcessor(mapper, 3)
+	p.WithPipe(pipe)
+
+	for i := 0; i < 3; i++ {
+		err := p.Process(streams.NewMessage("test", i).WithMetadata(src, offsetMetadata(i)))
+		assert.NoError(t, err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	close(release)
+	err := p.Close()
+
+	assert.NoError(t, err)
+	pipe.AssertExpectations()
+	msgs := pipe.Messages()
+	if assert.Len(t, msgs, 3) {
+		// The metadata is held back until the first message is forwarded.
+		s, meta := msgs[0].Msg.Metadata()
+		assert.Nil(t, s)
+		assert.Nil(t, meta)
+		s, meta = msgs[1].Msg.Metadata()
+		assert.Nil(t, s)
+		assert.Nil(t, meta)
+		s, meta = msgs[2].Msg.Metadata()
+		assert.Equal(t, src, s)
+		assert.Equal(t, offsetMetadata(2), meta)
+	}
+}
+
+func TestAsyncMapProcessor_Close(t *testing.T) {
+	p := streams.NewAsyncMapProcessor(nil, 1)
+
+	err := p.Close()
+
+	assert.NoError(t, err)
+}
+
 func TestMergeProcessor_Process(t *testing.T) {
 	pipe := mocks.NewPipe(t)
 	pipe.ExpectForward("test", "test")
@@ -208,6 +452,19 @@ func TestMergeProcessor_Process(t *testing.T) {
 	pipe.AssertExpectations()
 }
 
+func TestMergeProcessor_ProcessBatch(t *testing.T) {
+	pipe := mocks.NewPipe(t)
+	pipe.ExpectForward("test", 1)
+	pipe.ExpectForward("test", 2)
+	p := streams.NewMergeProcessor()
+	p.WithPipe(pipe)
+
+	err := p.(streams.BatchProcessor).ProcessBatch([]streams.Message{streams.NewMessage("test", 1), streams.NewMessage("test", 2)})
+
+	assert.NoError(t, err)
+	pipe.AssertExpectations()
+}
+
 func TestMergeProcessor_Close(t *testing.T) {
 	p := streams.NewMergeProcessor()
 
@@ -0,0 +1,280 @@
+// Package prometheus implements streams Stats with Prometheus collectors.
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rafalmnich/streams/v6"
+)
+
+// DefaultBucketFactor is the default growth factor of the native histogram buckets of timings.
+const DefaultBucketFactor = 1.1
+
+// DefaultBuckets are classic histogram buckets of timings, in seconds,
+// that can be enabled with WithBuckets.
+var DefaultBuckets = prometheus.ExponentialBuckets(0.00001, 4, 10)
+
+// metric represents the definition of a known metric.
+type metric struct {
+	help   string
+	labels []string
+}
+
+// metrics are the metrics reported by the streams Monitor.
+var metrics = map[string]metric{
+	"node.latency":          {help: "The latency of processing a message in a node.", labels: []string{"name"}},
+	"node.latency.p50":      {help: "The median latency of processing a message in a node over an interval.", labels: []string{"name"}},
+	"node.latency.p95":      {help: "The 95th percentile latency of processing a message in a node over an interval.", labels: []string{"name"}},
+	"node.latency.p99":      {help: "The 99th percentile latency of processing a message in a node over an interval.", labels: []string{"name"}},
+	"node.latency.max":      {help: "The maximum latency of processing a message in a node over an interval.", labels: []string{"name"}},
+	"node.throughput":       {help: "The number of messages processed by a node.", labels: []string{"name"}},
+	"node.errors":           {help: "The number of messages that failed processing in a node.", labels: []string{"name"}},
+	"node.back-pressure":    {help: "The percentage of the buffer of a node in use.", labels: []string{"name"}},
+	"commit.latency":        {help: "The latency of a commit."},
+	"commit.commits":        {help: "The number of commits.", labels: []string{"name"}},
+	"monitor.back-pressure": {help: "The percentage of the flush queue of the monitor in use."},
+	"monitor.dropped":       {help: "The number of events dropped by the monitor."},
+	"monitor.overhead":      {help: "The time spent by the monitor aggregating and flushing events."},
+}
+
+// StatsOptFunc represents a function that sets up the Stats.
+type StatsOptFunc func(s *Stats)
+
+// WithNamespace sets the namespace of the metrics. The default namespace is "streams".
+func WithNamespace(ns string) StatsOptFunc {
+	return func(s *Stats) {
+		s.namespace = ns
+	}
+}
+
+// WithRegistry sets the registry the collectors are registered with.
+func WithRegistry(reg *prometheus.Registry) StatsOptFunc {
+	return func(s *Stats) {
+		s.reg = reg
+	}
+}
+
+// WithBucketFactor sets the growth factor of the native histogram buckets of timings.
+// The factor must be greater than 1.
+func WithBucketFactor(factor float64) StatsOptFunc {
+	return func(s *Stats) {
+		s.factor = factor
+	}
+}
+
+// WithBuckets enables classic histogram buckets of timings, in seconds,
+// exposed alongside the native histograms.
+func WithBuckets(buckets []float64) StatsOptFunc {
+	return func(s *Stats) {
+		s.buckets = buckets
+	}
+}
+
+var _ = (streams.Stats)(&Stats{})
+
+// Stats represents a Prometheus stats instance.
+//
+// Counts are reported as counters suffixed with "_total", gauges as gauges,
+// and timings as native histograms in seconds suffixed with "_seconds". The tags
+// are reported as labels. The labels of a metric are fixed on its first use, after
+// which missing labels are reported empty and unknown labels are ignored.
+//
+// The streams Monitor reports a single aggregated value per node and interval,
+// such as the average or a percentile latency, so the histograms of the monitor
+// timings record one observation per interval rather than one per message.
+type Stats struct {
+	namespace string
+	reg       *prometheus.Registry
+	factor    float64
+	buckets   []float64
+
+	mu         sync.Mutex
+	counters   map[string]*prometheus.CounterVec
+	gauges     map[string]*prometheus.GaugeVec
+	histograms map[string]*prometheus.HistogramVec
+	labels     map[string][]string
+}
+
+// NewStats creates a new Prometheus Stats instance.
+func NewStats(opts ...StatsOptFunc) *Stats {
+	s := &Stats{
+		namespace:  "streams",
+		reg:        prometheus.NewRegistry(),
+		factor:     DefaultBucketFactor,
+		counters:   map[string]*prometheus.CounterVec{},
+		gauges:     map[string]*prometheus.GaugeVec{},
+		histograms: map[string]*prometheus.HistogramVec{},
+		labels:     map[string][]string{},
+	}
+
+	for _, optFn := range opts {
+		optFn(s)
+	}
+
+	return s
+}
+
+// Handler returns an HTTP handler exposing the metrics of the registry.
+func (s *Stats) Handler() http.Handler {
+	return promhttp.HandlerFor(s.reg, promhttp.HandlerOpts{})
+}
+
+// Inc increments a count by the value.
+func (s *Stats) Inc(name string, value int64, tags ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vec, ok := s.counters[name]
+	if !ok {
+		labels := s.labelNames(name, tags)
+		vec = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: s.namespace,
+			Name:      formatName(name) + "_total",
+			Help:      help(name),
+		}, labels)
+		if existing, ok := s.register(vec).(*prometheus.CounterVec); ok {
+			vec = existing
+		}
+		s.counters[name] = vec
+	}
+
+	vec.With(s.labelValues(name, tags)).Add(float64(value))
+}
+
+// Gauge measures the value of a metric.
+func (s *Stats) Gauge(name string, value float64, tags ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vec, ok := s.gauges[name]
+	if !ok {
+		labels := s.labelNames(name, tags)
+		vec = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: s.namespace,
+			Name:      formatName(name),
+			Help:      help(name),
+		}, labels)
+		if existing, ok := s.register(vec).(*prometheus.GaugeVec); ok {
+			vec = existing
+		}
+		s.gauges[name] = vec
+	}
+
+	vec.With(s.labelValues(name, tags)).Set(value)
+}
+
+// Timing sends the value of a Duration.
+//
+// Every call records a single observation in the histogram of the metric.
+func (s *Stats) Timing(name string, value time.Duration, tags ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vec, ok := s.histograms[name]
+	if !ok {
+		labels := s.labelNames(name, tags)
+		vec = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: s.namespace,
+			Name:      formatName(name) + "_seconds",
+			Help:      help(name),
+			Buckets:   s.buckets,
+
+			NativeHistogramBucketFactor:     s.factor,
+			NativeHistogramMaxBucketNumber:  160,
+			NativeHistogramMinResetDuration: time.Hour,
+		}, labels)
+		if existing, ok := s.register(vec).(*prometheus.HistogramVec); ok {
+			vec = existing
+		}
+		s.histograms[name] = vec
+	}
+
+	vec.With(s.labelValues(name, tags)).Observe(value.Seconds())
+}
+
+// register registers the collector, returning the collector already registered if any.
+//
+// A collector that cannot be registered is still returned, but its metrics are not exposed.
+//
+// The mutex must be held while calling register.
+func (s *Stats) register(c prometheus.Collector) prometheus.Collector {
+	if err := s.reg.Register(c); err != nil {
+		if exists, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return exists.ExistingCollector
+		}
+	}
+
+	return c
+}
+
+// labelNames fixes the label names of the metric.
+//
+// The mutex must be held while calling labelNames.
+func (s *Stats) labelNames(name string, tags []interface{}) []string {
+	labels, ok := s.labels[name]
+	if ok {
+		return labels
+	}
+
+	if m, ok := metrics[name]; ok {
+		labels = m.labels
+	} else {
+		seen := map[string]bool{}
+		for i := 0; i+1 < len(tags); i += 2 {
+			label := formatName(fmt.Sprint(tags[i]))
+			if !seen[label] {
+				seen[label] = true
+				labels = append(labels, label)
+			}
+		}
+		sort.Strings(labels)
+	}
+
+	s.labels[name] = labels
+
+	return labels
+}
+
+// labelValues returns the label values of the metric from the tags.
+//
+// The mutex must be held while calling labelValues.
+func (s *Stats) labelValues(name string, tags []interface{}) prometheus.Labels {
+	labels := s.labels[name]
+
+	values := make(prometheus.Labels, len(labels))
+	for _, label := range labels {
+		values[label] = ""
+	}
+
+	for i := 0; i+1 < len(tags); i += 2 {
+		key := formatName(fmt.Sprint(tags[i]))
+		if _, ok := values[key]; ok {
+			values[key] = fmt.Sprint(tags[i+1])
+		}
+	}
+
+	return values
+}
+
+// help returns the help of the metric.
+func help(name string) string {
+	if m, ok := metrics[name]; ok {
+		return m.help
+	}
+
+	return name
+}
+
+var nameReplacer = strings.NewReplacer(".", "_", "-", "_", ":", "_")
+
+// formatName formats the metric name as a Prometheus metric name.
+func formatName(name string) string {
+	return nameReplacer.Replace(name)
+}
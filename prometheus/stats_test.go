@@ -0,0 +1,159 @@
+package prometheus_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rafalmnich/streams/v6"
+	prom "github.com/rafalmnich/streams/v6/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats_ImplementsStatsInterface(t *testing.T) {
+	var s interface{} = prom.NewStats()
+
+	if _, ok := s.(streams.Stats); !ok {
+		t.Error("The Prometheus Stats should implement the streams.Stats interface.")
+	}
+}
+
+func TestStats_Inc(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	s := prom.NewStats(prom.WithRegistry(reg))
+
+	s.Inc("node.throughput", 2, "name", "map")
+	s.Inc("node.throughput", 3, "name", "map")
+	s.Inc("node.throughput", 1, "name", "filter")
+
+	expected := `
+# HELP streams_node_throughput_total The number of messages processed by a node.
+# TYPE streams_node_throughput_total counter
+streams_node_throughput_total{name="filter"} 1
+streams_node_throughput_total{name="map"} 5
+`
+	err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "streams_node_throughput_total")
+	assert.NoError(t, err)
+}
+
+func TestStats_Gauge(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	s := prom.NewStats(prom.WithRegistry(reg), prom.WithNamespace("test"))
+
+	s.Gauge("node.back-pressure", 10, "name", "map")
+	s.Gauge("node.back-pressure", 20, "name", "map")
+	s.Gauge("monitor.back-pressure", 5)
+
+	expected := `
+# HELP test_monitor_back_pressure The percentage of the flush queue of the monitor in use.
+# TYPE test_monitor_back_pressure gauge
+test_monitor_back_pressure 5
+# HELP test_node_back_pressure The percentage of the buffer of a node in use.
+# TYPE test_node_back_pressure gauge
+test_node_back_pressure{name="map"} 20
+`
+	err := testutil.GatherAndCompare(reg, strings.NewReader(expected))
+	assert.NoError(t, err)
+}
+
+func TestStats_Timing(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	s := prom.NewStats(prom.WithRegistry(reg), prom.WithBuckets([]float64{0.01, 1}))
+
+	s.Timing("commit.latency", 5*time.Millisecond)
+	s.Timing("commit.latency", 500*time.Millisecond)
+
+	expected := `
+# HELP streams_commit_latency_seconds The latency of a commit.
+# TYPE streams_commit_latency_seconds histogram
+streams_commit_latency_seconds_bucket{le="0.01"} 1
+streams_commit_latency_seconds_bucket{le="1"} 2
+streams_commit_latency_seconds_bucket{le="+Inf"} 2
+streams_commit_latency_seconds_sum 0.505
+streams_commit_latency_seconds_count 2
+`
+	err := testutil.GatherAndCompare(reg, strings.NewReader(expected))
+	assert.NoError(t, err)
+}
+
+func TestStats_TimingNativeHistogram(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	s := prom.NewStats(prom.WithRegistry(reg))
+
+	s.Timing("commit.latency", 5*time.Millisecond)
+	s.Timing("commit.latency", 500*time.Millisecond)
+
+	mfs, err := reg.Gather()
+	assert.NoError(t, err)
+	if assert.Len(t, mfs, 1) {
+		h := mfs[0].GetMetric()[0].GetHistogram()
+		assert.Equal(t, uint64(2), h.GetSampleCount())
+		assert.Empty(t, h.GetBucket())
+		assert.NotEmpty(t, h.GetPositiveSpan())
+		assert.Equal(t, int32(3), h.GetSchema())
+	}
+}
+
+func TestStats_UnknownMetricLabels(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	s := prom.NewStats(prom.WithRegistry(reg))
+
+	s.Inc("custom.count", 1, "topic", "test", "partition", 1, "topic", "dup")
+	s.Inc("custom.count", 1, "topic", "other", "unknown", "tag")
+
+	expected := `
+# HELP streams_custom_count_total custom.count
+# TYPE streams_custom_count_total counter
+streams_custom_count_total{partition="",topic="other"} 1
+streams_custom_count_total{partition="1",topic="dup"} 1
+`
+	err := testutil.GatherAndCompare(reg, strings.NewReader(expected))
+	assert.NoError(t, err)
+}
+
+func TestStats_SharedRegistry(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	s1 := prom.NewStats(prom.WithRegistry(reg))
+	s2 := prom.NewStats(prom.WithRegistry(reg))
+
+	s1.Inc("commit.commits", 1, "name", "streams:commit")
+	s2.Inc("commit.commits", 1, "name", "streams:commit")
+
+	count, err := testutil.GatherAndCount(reg, "streams_commit_commits_total")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestStats_Handler(t *testing.T) {
+	s := prom.NewStats()
+	s.Inc("node.throughput", 1, "name", "map")
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Contains(t, rec.Body.String(), `streams_node_throughput_total{name="map"} 1`)
+}
+
+func TestStats_WithMonitor(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	s := prom.NewStats(prom.WithRegistry(reg))
+	mon := streams.NewMonitor(s, time.Hour)
+
+	mon.Processed("map", time.Millisecond, 50)
+	mon.Committed(time.Millisecond)
+	_ = mon.Close()
+
+	count, err := testutil.GatherAndCount(reg,
+		"streams_node_latency_seconds",
+		"streams_node_throughput_total",
+		"streams_node_back_pressure",
+		"streams_commit_latency_seconds",
+		"streams_commit_commits_total",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, count)
+}
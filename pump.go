@@ -1,7 +1,12 @@
 package streams
 
 import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,6 +22,78 @@ type Pump interface {
 	Close() error
 }
 
+// BatchPump represents a Message pump that can accept batches of Messages.
+type BatchPump interface {
+	Pump
+
+	// AcceptBatch takes a batch of messages to be processed in the Pump.
+	AcceptBatch([]Message) error
+}
+
+// acceptBatch passes the batch to the pump, one by one if the pump does not accept batches.
+func acceptBatch(pump Pump, msgs []Message) error {
+	if bp, ok := pump.(BatchPump); ok {
+		return bp.AcceptBatch(msgs)
+	}
+
+	for _, msg := range msgs {
+		if err := pump.Accept(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// processBatch processes the batch, one by one if the processor does not process batches.
+func processBatch(proc Processor, msgs []Message) error {
+	if bp, ok := proc.(BatchProcessor); ok {
+		return bp.ProcessBatch(msgs)
+	}
+
+	for _, msg := range msgs {
+		if err := proc.Process(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// processedBatch adds a processed event for each message in a batch to the Monitor,
+// spreading the latency evenly across the messages.
+func processedBatch(mon Monitor, name string, n int, l time.Duration, bp float64) {
+	if n == 0 {
+		return
+	}
+
+	l /= time.Duration(n)
+	for i := 0; i < n; i++ {
+		mon.Processed(name, l, bp)
+	}
+}
+
+// drainer represents a processor that forwards messages after Process has returned.
+type drainer interface {
+	// drain waits for the messages in flight to be forwarded.
+	drain()
+}
+
+// drain waits for the messages in flight in the processor to be forwarded.
+//
+// This must happen before the pumps of the processor children are stopped.
+func drain(proc Processor) {
+	if d, ok := proc.(drainer); ok {
+		d.drain()
+	}
+}
+
+// queue represents a pump that queues messages before processing them.
+type queue interface {
+	// queueDepth returns the number of queued items and the capacity of the queue.
+	queueDepth() (depth, capacity int)
+}
+
 // syncPump is an synchronous Message Pump.
 type syncPump struct {
 	sync.Mutex
@@ -24,16 +101,25 @@ type syncPump struct {
 	name      string
 	processor Processor
 	pipe      TimedPipe
+	tracer    Tracer
 
 	mon Monitor
 }
 
 // NewSyncPump creates a new synchronous Pump instance.
-func NewSyncPump(mon Monitor, node Node, pipe TimedPipe) Pump {
+//
+// Only the tracer of the pump options applies to synchronous pumps.
+func NewSyncPump(mon Monitor, node Node, pipe TimedPipe, opts ...PumpOptFunc) Pump {
+	var o pumpOpts
+	for _, optFn := range opts {
+		optFn(&o)
+	}
+
 	p := &syncPump{
 		name:      node.Name(),
 		processor: node.Processor(),
 		pipe:      pipe,
+		tracer:    o.tracer,
 		mon:       mon,
 	}
 
@@ -44,9 +130,16 @@ func NewSyncPump(mon Monitor, node Node, pipe TimedPipe) Pump {
 func (p *syncPump) Accept(msg Message) error {
 	p.pipe.Reset()
 
+	msg, span := startSpan(p.tracer, p.name, msg)
+
 	start := nanotime()
 	err := p.processor.Process(msg)
+	nodeErr := nodeError(err)
+	span.End(nodeErr)
 	if err != nil {
+		if nodeErr != nil {
+			p.mon.Failed(p.name)
+		}
 		return err
 	}
 	latency := time.Duration(nanotime()-start) - p.pipe.Duration()
@@ -56,85 +149,265 @@ func (p *syncPump) Accept(msg Message) error {
 	return nil
 }
 
+// AcceptBatch takes a batch of messages to be processed in the Pump.
+func (p *syncPump) AcceptBatch(msgs []Message) error {
+	p.pipe.Reset()
+
+	msgs, span := startBatchSpans(p.tracer, p.name, msgs)
+
+	start := nanotime()
+	err := processBatch(p.processor, msgs)
+	nodeErr := nodeError(err)
+	span.End(nodeErr)
+	if err != nil {
+		if nodeErr != nil {
+			p.mon.Failed(p.name)
+		}
+		return err
+	}
+	latency := time.Duration(nanotime()-start) - p.pipe.Duration()
+
+	processedBatch(p.mon, p.name, len(msgs), latency, -1)
+
+	return nil
+}
+
 // Stop stops the pump, but does not close it.
-func (p *syncPump) Stop() {}
+func (p *syncPump) Stop() {
+	drain(p.processor)
+}
 
 // Close closes the pump.
 func (p *syncPump) Close() error {
 	return p.processor.Close()
 }
 
+// pumpItem represents a Message or a batch of Messages queued in a pump.
+type pumpItem struct {
+	msg   Message
+	batch []Message
+}
+
+// PumpOptFunc represents a function that sets up an asynchronous Pump.
+type PumpOptFunc func(o *pumpOpts)
+
+// WithBufferSize defines the size of the message buffer of the pump.
+//
+// Minimum size is 1.
+func WithBufferSize(size int) PumpOptFunc {
+	return func(o *pumpOpts) {
+		if size < 1 {
+			size = 1
+		}
+
+		o.bufferSize = size
+	}
+}
+
+// WithWorkers defines the number of workers processing messages concurrently in the pump.
+//
+// Messages are distributed to the workers by a hash of their key, so messages
+// with the same key are processed in order. Messages without a key are distributed
+// round robin. The processor must be safe for concurrent use when more than one
+// worker is used. Minimum number of workers is 1.
+//
+// The time spent forwarding to the children cannot be told apart between workers,
+// so the latency of a node with more than one worker includes it.
+func WithWorkers(n int) PumpOptFunc {
+	return func(o *pumpOpts) {
+		if n < 1 {
+			n = 1
+		}
+
+		o.workers = n
+	}
+}
+
+type pumpOpts struct {
+	bufferSize int
+	workers    int
+	tracer     Tracer
+}
+
 // asyncPump is an asynchronous Message Pump.
 type asyncPump struct {
-	sync.Mutex
+	// mu is held for reading by the workers while processing,
+	// and for writing by anyone locking the pump.
+	mu sync.RWMutex
 
 	name      string
 	processor Processor
 	pipe      TimedPipe
 	errFn     ErrorFunc
+	tracer    Tracer
 
 	mon Monitor
 
-	ch chan Message
+	chs  []chan pumpItem
+	next uint32
 
 	wg sync.WaitGroup
 }
 
 // NewAsyncPump creates a new asynchronous Pump instance.
-func NewAsyncPump(mon Monitor, node Node, pipe TimedPipe, errFn ErrorFunc) Pump {
+func NewAsyncPump(mon Monitor, node Node, pipe TimedPipe, errFn ErrorFunc, opts ...PumpOptFunc) Pump {
+	o := pumpOpts{
+		bufferSize: 1000,
+		workers:    1,
+	}
+	for _, optFn := range opts {
+		optFn(&o)
+	}
+
 	p := &asyncPump{
 		name:      node.Name(),
 		processor: node.Processor(),
 		pipe:      pipe,
 		errFn:     errFn,
+		tracer:    o.tracer,
 		mon:       mon,
-		ch:        make(chan Message, 1000),
+		chs:       make([]chan pumpItem, o.workers),
 	}
 
-	go p.run()
+	for i := range p.chs {
+		p.chs[i] = make(chan pumpItem, o.bufferSize)
+
+		p.wg.Add(1)
+		go p.run(p.chs[i])
+	}
 
 	return p
 }
 
-func (p *asyncPump) run() {
-	p.wg.Add(1)
+// Lock locks the pump, waiting for the messages being processed to finish.
+func (p *asyncPump) Lock() {
+	p.mu.Lock()
+}
+
+// Unlock unlocks the pump.
+func (p *asyncPump) Unlock() {
+	p.mu.Unlock()
+}
+
+func (p *asyncPump) run(ch chan pumpItem) {
 	defer p.wg.Done()
 
-	for msg := range p.ch {
-		p.pipe.Reset()
+	// The pipe is shared by the workers, so its duration is only
+	// meaningful when there is a single worker.
+	timed := len(p.chs) == 1
 
-		p.Lock()
+	for item := range ch {
+		if timed {
+			p.pipe.Reset()
+		}
+
+		p.mu.RLock()
 
 		start := nanotime()
-		err := p.processor.Process(msg)
+		n, err := p.process(item)
 		if err != nil {
-			p.Unlock()
+			p.mu.RUnlock()
+			p.mon.Failed(p.name)
 			p.errFn(err)
 
+			// Discard the remaining messages, so the pumps
+			// feeding this pump do not block until it is stopped.
+			for range ch {
+			}
+
 			return
 		}
-		latency := time.Duration(nanotime()-start) - p.pipe.Duration()
+		latency := time.Duration(nanotime() - start)
+		if timed {
+			latency -= p.pipe.Duration()
+		}
 
-		p.Unlock()
+		p.mu.RUnlock()
 
-		p.mon.Processed(p.name, latency, pressure(p.ch))
+		processedBatch(p.mon, p.name, n, latency, pressure(ch))
 	}
 
 	// It is not safe to do anything after the loop
 }
 
+// process processes the queued item, returning the number of processed messages.
+func (p *asyncPump) process(item pumpItem) (int, error) {
+	if item.batch == nil {
+		msg, span := startSpan(p.tracer, p.name, item.msg)
+		err := p.processor.Process(msg)
+		span.End(err)
+
+		return 1, err
+	}
+
+	batch, span := startBatchSpans(p.tracer, p.name, item.batch)
+	err := processBatch(p.processor, batch)
+	span.End(err)
+
+	return len(batch), err
+}
+
+// worker returns the index of the worker the message belongs to.
+func (p *asyncPump) worker(msg Message) int {
+	if len(p.chs) == 1 {
+		return 0
+	}
+
+	if msg.Key == nil {
+		return int(atomic.AddUint32(&p.next, 1) % uint32(len(p.chs)))
+	}
+
+	return int(keyHash(msg.Key) % uint32(len(p.chs)))
+}
+
 // Accept takes a message to be processed in the Pump.
 func (p *asyncPump) Accept(msg Message) error {
-	p.ch <- msg
+	p.chs[p.worker(msg)] <- pumpItem{msg: msg}
+
+	return nil
+}
+
+// AcceptBatch takes a batch of messages to be processed in the Pump.
+//
+// When the pump has more than one worker, the batch is split between
+// the workers, keeping the order of the messages.
+func (p *asyncPump) AcceptBatch(msgs []Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	if len(p.chs) == 1 {
+		p.chs[0] <- pumpItem{batch: msgs}
+
+		return nil
+	}
+
+	batches := make([][]Message, len(p.chs))
+	for _, msg := range msgs {
+		i := p.worker(msg)
+		batches[i] = append(batches[i], msg)
+	}
+
+	for i, batch := range batches {
+		if len(batch) == 0 {
+			continue
+		}
+
+		p.chs[i] <- pumpItem{batch: batch}
+	}
 
 	return nil
 }
 
 // Stop stops the pump, but does not close it.
 func (p *asyncPump) Stop() {
-	close(p.ch)
+	for _, ch := range p.chs {
+		close(ch)
+	}
 
 	p.wg.Wait()
+
+	drain(p.processor)
 }
 
 // Close closes the pump.
@@ -144,18 +417,50 @@ func (p *asyncPump) Close() error {
 	return p.processor.Close()
 }
 
+// queueDepth returns the number of queued items and the capacity of the queue.
+func (p *asyncPump) queueDepth() (depth, capacity int) {
+	for _, ch := range p.chs {
+		depth += len(ch)
+		capacity += cap(ch)
+	}
+
+	return depth, capacity
+}
+
 // pressure calculates how full a channel is.
-func pressure(ch chan Message) float64 {
+func pressure(ch chan pumpItem) float64 {
 	l := float64(len(ch))
 	c := float64(cap(ch))
 
 	return l / c * 100
 }
 
+// keyHash calculates the hash of a message key.
+func keyHash(key interface{}) uint32 {
+	h := fnv.New32a()
+
+	switch k := key.(type) {
+	case string:
+		_, _ = h.Write([]byte(k))
+	case []byte:
+		_, _ = h.Write(k)
+	default:
+		_, _ = fmt.Fprint(h, k)
+	}
+
+	return h.Sum32()
+}
+
 // SourcePump represents a Message pump for sources.
 type SourcePump interface {
+	// Pause stops the source pump from consuming, without closing it.
+	Pause()
+	// Resume resumes consuming after a Pause.
+	Resume()
 	// Stop stops the source pump from running.
 	Stop()
+	// Done returns a channel that is closed when the source is exhausted.
+	Done() <-chan struct{}
 	// Close closed the source pump.
 	Close() error
 }
@@ -163,6 +468,20 @@ type SourcePump interface {
 // SourcePumps represents a set of source pumps.
 type SourcePumps []SourcePump
 
+// PauseAll pauses all source pumps.
+func (p SourcePumps) PauseAll() {
+	for _, sp := range p {
+		sp.Pause()
+	}
+}
+
+// ResumeAll resumes all source pumps.
+func (p SourcePumps) ResumeAll() {
+	for _, sp := range p {
+		sp.Resume()
+	}
+}
+
 // StopAll stops all source pumps.
 func (p SourcePumps) StopAll() {
 	for _, sp := range p {
@@ -179,71 +498,212 @@ type sourcePump struct {
 
 	mon Monitor
 
-	quit chan struct{}
-	wg   sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// runCancel stops the current run, and is nil while the pump is paused.
+	mu        sync.Mutex
+	runCancel context.CancelFunc
+	wg        sync.WaitGroup
+
+	done     chan struct{}
+	doneOnce sync.Once
 }
 
 // NewSourcePump creates a new SourcePump.
 func NewSourcePump(mon Monitor, name string, source Source, pumps []Pump, errFn ErrorFunc) SourcePump {
+	return newSourcePump(context.Background(), mon, name, source, pumps, errFn)
+}
+
+// newSourcePump creates a new SourcePump that stops running when the context is done.
+func newSourcePump(ctx context.Context, mon Monitor, name string, source Source, pumps []Pump, errFn ErrorFunc) SourcePump {
+	ctx, cancel := context.WithCancel(ctx)
+
 	p := &sourcePump{
 		name:   name,
 		source: source,
 		pumps:  pumps,
 		errFn:  errFn,
 		mon:    mon,
-		quit:   make(chan struct{}, 2),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
 	}
 
-	go p.run()
+	p.start()
 
 	return p
 }
 
-func (p *sourcePump) run() {
+// start starts running the pump, if it is not already running.
+func (p *sourcePump) start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.runCancel != nil {
+		return
+	}
+
+	select {
+	case <-p.done:
+		// The source is exhausted.
+		return
+	default:
+	}
+
+	ctx, cancel := context.WithCancel(p.ctx)
+	p.runCancel = cancel
+
 	p.wg.Add(1)
+	go p.run(ctx)
+}
+
+func (p *sourcePump) run(ctx context.Context) {
 	defer p.wg.Done()
 
+	batchSrc, isBatch := p.source.(BatchSource)
+
 	for {
 		select {
-		case <-p.quit:
+		case <-ctx.Done():
 			return
 		default:
-			start := nanotime()
+			var err error
+			if isBatch {
+				err = p.pumpBatch(ctx, batchSrc)
+			} else {
+				err = p.pumpMessage(ctx)
+			}
+
+			if err == io.EOF {
+				p.doneOnce.Do(func() {
+					close(p.done)
+				})
+				return
+			}
 
-			msg, err := p.source.Consume()
 			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
 				go p.errFn(err)
 				return
 			}
+		}
+	}
+}
 
-			if msg.Empty() {
-				continue
-			}
+// pumpMessage consumes a single Message and passes it to the pumps.
+func (p *sourcePump) pumpMessage(ctx context.Context) error {
+	start := nanotime()
 
-			latency := time.Duration(nanotime() - start)
-			p.mon.Processed(p.name, latency, -1)
+	msg, err := p.consume(ctx)
+	if err != nil && err != io.EOF {
+		if ctx.Err() == nil {
+			p.mon.Failed(p.name)
+		}
+		return err
+	}
 
-			for _, pump := range p.pumps {
-				err = pump.Accept(msg)
-				if err != nil {
-					go p.errFn(err)
-					return
-				}
-			}
+	if msg.Empty() {
+		return err
+	}
+
+	latency := time.Duration(nanotime() - start)
+	p.mon.Processed(p.name, latency, -1)
+
+	for _, pump := range p.pumps {
+		if err := pump.Accept(msg); err != nil {
+			return unwrapForwarded(err)
 		}
 	}
+
+	return err
+}
+
+// pumpBatch consumes a batch of Messages and passes it to the pumps.
+func (p *sourcePump) pumpBatch(ctx context.Context, src BatchSource) error {
+	start := nanotime()
+
+	msgs, err := consumeBatch(ctx, src)
+	if err != nil && err != io.EOF {
+		if ctx.Err() == nil {
+			p.mon.Failed(p.name)
+		}
+		return err
+	}
+
+	if len(msgs) == 0 {
+		return err
+	}
+
+	latency := time.Duration(nanotime() - start)
+	processedBatch(p.mon, p.name, len(msgs), latency, -1)
+
+	for _, pump := range p.pumps {
+		if err := acceptBatch(pump, msgs); err != nil {
+			return unwrapForwarded(err)
+		}
+	}
+
+	return err
+}
+
+// consumeBatch gets the next batch of Messages from the source, blocking if the source supports it.
+func consumeBatch(ctx context.Context, src BatchSource) ([]Message, error) {
+	if csrc, ok := src.(ContextBatchSource); ok {
+		return csrc.ConsumeBatchContext(ctx)
+	}
+
+	return src.ConsumeBatch()
+}
+
+// consume gets the next Message from the source, blocking if the source supports it.
+func (p *sourcePump) consume(ctx context.Context) (Message, error) {
+	if src, ok := p.source.(ContextSource); ok {
+		return src.ConsumeContext(ctx)
+	}
+
+	return p.source.Consume()
+}
+
+// Pause stops the source pump from consuming, without closing it.
+//
+// Pause blocks until the message being consumed has been passed to the pumps.
+func (p *sourcePump) Pause() {
+	p.mu.Lock()
+	cancel := p.runCancel
+	p.runCancel = nil
+	p.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	p.wg.Wait()
+}
+
+// Resume resumes consuming after a Pause.
+func (p *sourcePump) Resume() {
+	p.start()
 }
 
 // Stop stops the source pump from running.
 func (p *sourcePump) Stop() {
-	p.quit <- struct{}{}
+	p.cancel()
 
-	p.wg.Wait()
+	p.Pause()
+}
+
+// Done returns a channel that is closed when the source is exhausted.
+func (p *sourcePump) Done() <-chan struct{} {
+	return p.done
 }
 
 // Close closes the source pump.
 func (p *sourcePump) Close() error {
-	close(p.quit)
+	p.cancel()
 
 	return p.source.Close()
 }
@@ -1,7 +1,9 @@
 package streams_test
 
 import (
+	"context"
 	"errors"
+	"io"
 	"testing"
 	"time"
 
@@ -48,6 +50,82 @@ func TestSyncPump_AcceptError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestSyncPump_AcceptErrorReportsFailure(t *testing.T) {
+	mon := new(MockMonitor)
+	mon.On("Failed", "test").Once()
+	msg := streams.NewMessage("test", "test")
+	processor := new(MockProcessor)
+	processor.On("Process", msg).Return(errors.New("test"))
+	node := streams.NewProcessorNode("test", processor)
+	pipe := new(MockTimedPipe)
+	pipe.On("Reset")
+	pipe.On("Duration").Return(time.Duration(0))
+	p := streams.NewSyncPump(mon, node, pipe)
+
+	err := p.Accept(msg)
+
+	assert.Error(t, err)
+	mon.AssertExpectations(t)
+}
+
+func TestSyncPump_AcceptForwardedErrorReportsFailureOnce(t *testing.T) {
+	leafErr := errors.New("test")
+	mon := new(MockMonitor)
+	mon.On("Failed", "leaf").Once()
+	msg := streams.NewMessage("test", "test")
+	leafProc := new(MockProcessor)
+	leafProc.On("Process", msg).Return(leafErr)
+	leafPipe := new(MockTimedPipe)
+	leafPipe.On("Reset")
+	leafPump := streams.NewSyncPump(mon, streams.NewProcessorNode("leaf", leafProc), leafPipe)
+	parentProc := streams.NewMapProcessor(streams.MapperFunc(func(msg streams.Message) (streams.Message, error) {
+		return msg, nil
+	}))
+	parentPipe := streams.NewPipe(new(MockMetastore), new(MockSupervisor), parentProc, []streams.Pump{leafPump})
+	parentProc.WithPipe(parentPipe)
+	p := streams.NewSyncPump(mon, streams.NewProcessorNode("parent", parentProc), parentPipe.(streams.TimedPipe))
+
+	err := p.Accept(msg)
+
+	assert.True(t, errors.Is(err, leafErr))
+	mon.AssertExpectations(t)
+}
+
+func TestSyncPump_AcceptBatch(t *testing.T) {
+	mon := new(MockMonitor)
+	mon.On("Processed", "test", mock.Anything, float64(-1)).Return(nil).Twice()
+	msgs := []streams.Message{streams.NewMessage("test", 1), streams.NewMessage("test", 2)}
+	processor := new(MockBatchProcessor)
+	processor.On("ProcessBatch", msgs).Return(nil)
+	node := streams.NewProcessorNode("test", processor)
+	pipe := new(MockTimedPipe)
+	pipe.On("Reset")
+	pipe.On("Duration").Return(time.Duration(0))
+	p := streams.NewSyncPump(mon, node, pipe)
+
+	err := p.(streams.BatchPump).AcceptBatch(msgs)
+
+	assert.NoError(t, err)
+	processor.AssertExpectations(t)
+	mon.AssertExpectations(t)
+}
+
+func TestSyncPump_AcceptBatchError(t *testing.T) {
+	msgs := []streams.Message{streams.NewMessage("test", 1), streams.NewMessage("test", 2)}
+	processor := new(MockProcessor)
+	processor.On("Process", msgs[0]).Return(errors.New("test"))
+	node := streams.NewProcessorNode("test", processor)
+	pipe := new(MockTimedPipe)
+	pipe.On("Reset")
+	pipe.On("Duration").Return(time.Duration(0))
+	p := streams.NewSyncPump(&fakeMonitor{}, node, pipe)
+
+	err := p.(streams.BatchPump).AcceptBatch(msgs)
+
+	assert.Error(t, err)
+	processor.AssertNotCalled(t, "Process", msgs[1])
+}
+
 func TestSyncPump_Close(t *testing.T) {
 	processor := new(MockProcessor)
 	processor.On("Close").Return(nil)
@@ -96,6 +174,129 @@ func TestAsyncPump_Accept(t *testing.T) {
 	mon.AssertExpectations(t)
 }
 
+func TestAsyncPump_AcceptBatch(t *testing.T) {
+	mon := new(MockMonitor)
+	mon.On("Processed", "test", mock.Anything, mock.Anything).Return(nil).Twice()
+	msgs := []streams.Message{streams.NewMessage("test", 1), streams.NewMessage("test", 2)}
+	processor := new(MockProcessor)
+	processor.On("Process", msgs[0]).Return(nil)
+	processor.On("Process", msgs[1]).Return(nil)
+	processor.On("Close").Maybe().Return(nil)
+	node := streams.NewProcessorNode("test", processor)
+	pipe := new(MockTimedPipe)
+	pipe.On("Reset")
+	pipe.On("Duration").Return(time.Duration(0))
+	p := streams.NewAsyncPump(mon, node, pipe, func(error) {})
+	defer p.Close()
+
+	err := p.(streams.BatchPump).AcceptBatch(msgs)
+
+	time.Sleep(3 * time.Millisecond)
+
+	assert.NoError(t, err)
+	processor.AssertExpectations(t)
+	mon.AssertExpectations(t)
+}
+
+func TestAsyncPump_WorkersKeepKeyOrder(t *testing.T) {
+	processor := newRecordingProcessor()
+	node := streams.NewProcessorNode("test", processor)
+	pipe := new(MockTimedPipe)
+	pipe.On("Reset")
+	pipe.On("Duration").Return(time.Duration(0))
+	p := streams.NewAsyncPump(&fakeMonitor{}, node, pipe, func(error) {}, streams.WithWorkers(4), streams.WithBufferSize(10))
+
+	keys := []string{"a", "b", "c", "d", "e"}
+	var want []interface{}
+	for i := 0; i < 100; i++ {
+		want = append(want, i)
+		for _, key := range keys {
+			_ = p.Accept(streams.NewMessage(key, i))
+		}
+	}
+	p.Stop()
+
+	for _, key := range keys {
+		assert.Equal(t, want, processor.msgs[key])
+	}
+}
+
+func TestAsyncPump_WorkersDoNotSubtractSharedPipeDuration(t *testing.T) {
+	mon := new(MockMonitor)
+	mon.On("Processed", "test", mock.MatchedBy(func(l time.Duration) bool { return l >= 0 }), mock.Anything).Twice()
+	processor := newRecordingProcessor()
+	node := streams.NewProcessorNode("test", processor)
+	pipe := new(MockTimedPipe)
+	p := streams.NewAsyncPump(mon, node, pipe, func(error) {}, streams.WithWorkers(2))
+
+	_ = p.Accept(streams.NewMessage("a", 1))
+	_ = p.Accept(streams.NewMessage("b", 2))
+	p.Stop()
+
+	pipe.AssertNotCalled(t, "Reset")
+	pipe.AssertNotCalled(t, "Duration")
+	mon.AssertExpectations(t)
+}
+
+func TestAsyncPump_WorkersAcceptBatch(t *testing.T) {
+	processor := newRecordingProcessor()
+	node := streams.NewProcessorNode("test", processor)
+	pipe := new(MockTimedPipe)
+	pipe.On("Reset")
+	pipe.On("Duration").Return(time.Duration(0))
+	p := streams.NewAsyncPump(&fakeMonitor{}, node, pipe, func(error) {}, streams.WithWorkers(3))
+
+	var msgs []streams.Message
+	for i := 0; i < 10; i++ {
+		msgs = append(msgs, streams.NewMessage("a", i), streams.NewMessage("b", i))
+	}
+
+	err := p.(streams.BatchPump).AcceptBatch(msgs)
+	p.Stop()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, processor.msgs["a"])
+	assert.Equal(t, []interface{}{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, processor.msgs["b"])
+}
+
+func TestAsyncPump_WorkersDistributeMessagesWithoutKey(t *testing.T) {
+	processor := newRecordingProcessor()
+	node := streams.NewProcessorNode("test", processor)
+	pipe := new(MockTimedPipe)
+	pipe.On("Reset")
+	pipe.On("Duration").Return(time.Duration(0))
+	p := streams.NewAsyncPump(&fakeMonitor{}, node, pipe, func(error) {}, streams.WithWorkers(2))
+
+	for i := 0; i < 10; i++ {
+		_ = p.Accept(streams.NewMessage(nil, i))
+	}
+	p.Stop()
+
+	assert.Len(t, processor.msgs[nil], 10)
+}
+
+func TestAsyncPump_LockWaitsForWorkers(t *testing.T) {
+	processor := newRecordingProcessor()
+	node := streams.NewProcessorNode("test", processor)
+	pipe := new(MockTimedPipe)
+	pipe.On("Reset")
+	pipe.On("Duration").Return(time.Duration(0))
+	p := streams.NewAsyncPump(&fakeMonitor{}, node, pipe, func(error) {}, streams.WithWorkers(2))
+
+	p.Lock()
+	_ = p.Accept(streams.NewMessage("a", 1))
+	time.Sleep(3 * time.Millisecond)
+
+	processor.mu.Lock()
+	assert.Len(t, processor.msgs["a"], 0)
+	processor.mu.Unlock()
+
+	p.Unlock()
+	p.Stop()
+
+	assert.Len(t, processor.msgs["a"], 1)
+}
+
 func TestAsyncPump_AcceptError(t *testing.T) {
 	var err error
 
@@ -119,6 +320,27 @@ func TestAsyncPump_AcceptError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestAsyncPump_AcceptErrorReportsFailure(t *testing.T) {
+	mon := new(MockMonitor)
+	mon.On("Failed", "test").Once()
+	msg := streams.NewMessage("test", "test")
+	processor := new(MockProcessor)
+	processor.On("Process", msg).Return(errors.New("test"))
+	processor.On("Close").Return(nil)
+	node := streams.NewProcessorNode("test", processor)
+	pipe := new(MockTimedPipe)
+	pipe.On("Reset")
+	pipe.On("Duration").Return(time.Duration(0))
+	p := streams.NewAsyncPump(mon, node, pipe, func(error) {})
+	defer p.Close()
+
+	_ = p.Accept(msg)
+
+	time.Sleep(time.Millisecond)
+
+	mon.AssertExpectations(t)
+}
+
 func TestAsyncPump_Close(t *testing.T) {
 	processor := new(MockProcessor)
 	processor.On("Close").Return(nil)
@@ -175,6 +397,160 @@ func TestSourcePump_CanConsume(t *testing.T) {
 	mon.AssertExpectations(t)
 }
 
+func TestSourcePump_PauseAndResume(t *testing.T) {
+	msgs := make(chan streams.Message)
+	source := &chanSource{msgs: msgs}
+	pump := new(MockPump)
+	pump.On("Accept", mock.Anything).Return(nil)
+	p := streams.NewSourcePump(&fakeMonitor{}, "test", source, []streams.Pump{pump}, func(error) {})
+	defer p.Close()
+	defer p.Stop()
+
+	p.Pause()
+
+	select {
+	case msgs <- streams.NewMessage("test", "test"):
+		assert.Fail(t, "Expected paused source pump not to consume")
+	case <-time.After(5 * time.Millisecond):
+	}
+
+	p.Resume()
+
+	select {
+	case msgs <- streams.NewMessage("test", "test"):
+	case <-time.After(time.Second):
+		assert.Fail(t, "Expected resumed source pump to consume")
+	}
+}
+
+func TestSourcePump_DoneOnEOF(t *testing.T) {
+	mon := new(MockMonitor)
+	mon.On("Processed", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	msg := streams.NewMessage("test", "test")
+	source := new(MockSource)
+	source.On("Consume").Once().Return(msg, io.EOF)
+	source.On("Close").Return(nil)
+	pump := new(MockPump)
+	pump.On("Accept", msg).Return(nil)
+	p := streams.NewSourcePump(mon, "test", source, []streams.Pump{pump}, func(error) {
+		assert.FailNow(t, "Expected end of stream not to be an error")
+	})
+	defer p.Close()
+	defer p.Stop()
+
+	select {
+	case <-p.Done():
+	case <-time.After(time.Second):
+		assert.FailNow(t, "Expected source pump to be done")
+	}
+
+	source.AssertCalled(t, "Consume")
+	pump.AssertExpectations(t)
+}
+
+func TestSourcePump_PrefersContextSource(t *testing.T) {
+	mon := new(MockMonitor)
+	mon.On("Processed", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	msg := streams.NewMessage("test", "test")
+	source := new(MockContextSource)
+	source.On("ConsumeContext", mock.Anything).Maybe().Return(msg, nil)
+	source.On("Close").Return(nil)
+	pump := new(MockPump)
+	pump.On("Accept", msg).Return(nil)
+	p := streams.NewSourcePump(mon, "test", source, []streams.Pump{pump}, func(error) {})
+	defer p.Close()
+	defer p.Stop()
+
+	time.Sleep(time.Millisecond)
+
+	pump.AssertExpectations(t)
+	source.AssertNotCalled(t, "Consume")
+}
+
+func TestSourcePump_StopUnblocksContextSource(t *testing.T) {
+	gotError := false
+	block := func(ctx context.Context) streams.Message {
+		<-ctx.Done()
+		return streams.EmptyMessage
+	}
+	source := new(MockContextSource)
+	source.On("ConsumeContext", mock.Anything).Return(block, context.Canceled)
+	source.On("Close").Return(nil)
+	p := streams.NewSourcePump(&fakeMonitor{}, "test", source, []streams.Pump{}, func(error) {
+		gotError = true
+	})
+
+	time.Sleep(time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		p.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the source pump to stop")
+	}
+	assert.NoError(t, p.Close())
+	assert.False(t, gotError)
+}
+
+func TestSourcePump_CanConsumeBatch(t *testing.T) {
+	mon := new(MockMonitor)
+	mon.On("Processed", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	msgs := []streams.Message{streams.NewMessage("test", 1), streams.NewMessage("test", 2)}
+	source := new(MockBatchSource)
+	source.On("ConsumeBatch").Maybe().Return(msgs, nil)
+	source.On("Close").Return(nil)
+	batchPump := new(MockBatchPump)
+	batchPump.On("AcceptBatch", msgs).Return(nil)
+	pump := new(MockPump)
+	pump.On("Accept", msgs[0]).Return(nil)
+	pump.On("Accept", msgs[1]).Return(nil)
+	p := streams.NewSourcePump(mon, "test", source, []streams.Pump{batchPump, pump}, func(error) {})
+	defer p.Close()
+	defer p.Stop()
+
+	time.Sleep(time.Millisecond)
+
+	batchPump.AssertExpectations(t)
+	pump.AssertExpectations(t)
+	source.AssertNotCalled(t, "Consume")
+}
+
+func TestSourcePump_StopUnblocksContextBatchSource(t *testing.T) {
+	gotError := false
+	block := func(ctx context.Context) []streams.Message {
+		<-ctx.Done()
+		return nil
+	}
+	source := new(MockContextBatchSource)
+	source.On("ConsumeBatchContext", mock.Anything).Return(block, context.Canceled)
+	source.On("Close").Return(nil)
+	p := streams.NewSourcePump(&fakeMonitor{}, "test", source, []streams.Pump{}, func(error) {
+		gotError = true
+	})
+
+	time.Sleep(time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		p.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the source pump to stop")
+	}
+	assert.NoError(t, p.Close())
+	assert.False(t, gotError)
+	source.AssertNotCalled(t, "ConsumeBatch")
+}
+
 func TestSourcePump_HandlesPumpError(t *testing.T) {
 	gotError := false
 	msg := streams.NewMessage("test", "test")
@@ -194,6 +570,35 @@ func TestSourcePump_HandlesPumpError(t *testing.T) {
 	assert.True(t, gotError)
 }
 
+func TestSourcePump_UnwrapsForwardedPumpError(t *testing.T) {
+	leafErr := errors.New("test")
+	msg := streams.NewMessage("test", "test")
+	source := new(MockSource)
+	source.On("Consume").Maybe().Return(msg, nil)
+	source.On("Close").Return(nil)
+	leaf := new(MockPump)
+	leaf.On("Accept", msg).Return(leafErr)
+	proc := streams.NewMapProcessor(streams.MapperFunc(func(msg streams.Message) (streams.Message, error) {
+		return msg, nil
+	}))
+	pipe := streams.NewPipe(new(MockMetastore), new(MockSupervisor), proc, []streams.Pump{leaf})
+	proc.WithPipe(pipe)
+	pump := streams.NewSyncPump(&fakeMonitor{}, streams.NewProcessorNode("test", proc), pipe.(streams.TimedPipe))
+	errs := make(chan error, 1)
+	p := streams.NewSourcePump(&fakeMonitor{}, "test", source, []streams.Pump{pump}, func(err error) {
+		errs <- err
+	})
+	defer p.Close()
+	defer p.Stop()
+
+	select {
+	case err := <-errs:
+		assert.Equal(t, leafErr, err)
+	case <-time.After(time.Second):
+		assert.FailNow(t, "Expected the error handler to be called")
+	}
+}
+
 func TestSourcePump_Close(t *testing.T) {
 	source := new(MockSource)
 	source.On("Consume").Maybe().Return(streams.NewMessage("test", "test"), nil)
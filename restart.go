@@ -0,0 +1,231 @@
+package streams
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// SourceFactory creates a Source.
+type SourceFactory func() (Source, error)
+
+// RestartPolicy represents the policy of restarting a Task on errors.
+//
+// On restart, the pumps of the task are torn down without committing, the
+// sources with a factory are closed and re-created, and the pumps are rebuilt.
+// Consuming resumes from the last committed metadata of the re-created sources.
+// The processors are reused and are not closed.
+//
+// Sources without a factory are reused as they are. A source that cannot
+// recover from its own failure, such as a kafka Source, fails again on the
+// first consume after the restart, spending a restart on every backoff, and
+// must be given a factory.
+type RestartPolicy struct {
+	// MaxRestarts is the maximum number of consecutive restarts. Once exhausted,
+	// errors are passed to the error handler.
+	MaxRestarts int
+	// ResetAfter is the time a rebuilt topology must run without errors for
+	// the restarts to be reset. If it is not set, the restarts are never reset
+	// and MaxRestarts limits the restarts over the lifetime of the task.
+	ResetAfter time.Duration
+	// MinBackoff is the delay before the first restart. The delay is doubled on every restart.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum delay before a restart.
+	MaxBackoff time.Duration
+	// Recoverable determines if the task can be restarted after an error.
+	// If it is not set, all errors are recoverable.
+	Recoverable func(error) bool
+	// Sources maps the names of the source nodes to the factories
+	// re-creating their sources. Sources without a factory are reused.
+	Sources map[string]SourceFactory
+}
+
+// backoff returns the delay before the nth restart.
+func (p *RestartPolicy) backoff(n int) time.Duration {
+	d := p.MinBackoff
+	for i := 0; i < n && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+
+	return d
+}
+
+// resetRestarts resets the restarts if the rebuilt topology has run
+// without errors for longer than the reset duration of the policy.
+func (t *streamTask) resetRestarts() {
+	p := t.restartPolicy
+	if p == nil || p.ResetAfter <= 0 {
+		return
+	}
+
+	at := atomic.LoadInt64(&t.rebuiltAt)
+	if at == 0 || time.Since(time.Unix(0, at)) < p.ResetAfter {
+		return
+	}
+
+	atomic.StoreInt32(&t.restarts, 0)
+}
+
+// canRestart determines if the task can be restarted after the error.
+func (t *streamTask) canRestart(err error) bool {
+	p := t.restartPolicy
+	if p == nil || int(atomic.LoadInt32(&t.restarts)) >= p.MaxRestarts {
+		return false
+	}
+
+	return p.Recoverable == nil || p.Recoverable(err)
+}
+
+// restart tears down the topology and rebuilds it after a backoff,
+// until it is rebuilt or the restart budget is exhausted.
+func (t *streamTask) restart() {
+	defer atomic.StoreInt32(&t.restarting, 0)
+
+	t.mu.Lock()
+	if t.torndown || t.closed {
+		t.mu.Unlock()
+		return
+	}
+	t.teardownTopology()
+	t.setState(StateRestarting)
+	atomic.StoreInt64(&t.rebuiltAt, 0)
+	t.mu.Unlock()
+
+	for {
+		n := atomic.AddInt32(&t.restarts, 1)
+
+		select {
+		case <-t.closing:
+			return
+		case <-t.ctx.Done():
+			t.fail(t.ctx.Err())
+
+			return
+		case <-time.After(t.restartPolicy.backoff(int(n - 1))):
+		}
+
+		t.mu.Lock()
+		err := t.rebuildTopology()
+		t.mu.Unlock()
+
+		if err == nil {
+			atomic.StoreInt64(&t.rebuiltAt, time.Now().UnixNano())
+
+			return
+		}
+
+		if !t.canRestart(err) {
+			t.fail(err)
+
+			return
+		}
+	}
+}
+
+// teardownTopology stops the pumps without committing and closes the sources with a factory.
+//
+// The mutex must be held while calling teardownTopology.
+func (t *streamTask) teardownTopology() {
+	atomic.AddUint32(&t.gen, 1)
+	t.stopWatching()
+
+	_ = t.supervisor.Close()
+
+	t.srcPumps.StopAll()
+	t.stopPumps()
+
+	for source, node := range t.sources {
+		if _, ok := t.restartPolicy.Sources[node.Name()]; ok {
+			_ = source.Close()
+		}
+	}
+
+	t.srcPumps = SourcePumps{}
+	t.pumps = map[Node]Pump{}
+	t.torndown = true
+}
+
+// rebuildTopology re-creates the sources with a factory and sets up the topology.
+//
+// The mutex must be held while calling rebuildTopology.
+func (t *streamTask) rebuildTopology() error {
+	if t.closed || t.isClosing() {
+		return nil
+	}
+
+	sources := make(map[Source]Node, len(t.sources))
+	for source, node := range t.sources {
+		if fn, ok := t.restartPolicy.Sources[node.Name()]; ok {
+			newSource, err := fn()
+			if err != nil {
+				closeFactorySources(sources, t.restartPolicy.Sources)
+				return err
+			}
+			source = newSource
+		}
+
+		sources[source] = node
+	}
+
+	t.sources = sources
+	t.store = NewMetastore()
+	t.supervisor = t.newSupervisor()
+	t.setupTopology(t.ctx)
+	t.torndown = false
+
+	if t.paused {
+		t.srcPumps.PauseAll()
+		t.setState(StatePaused)
+	} else {
+		t.setState(StateRunning)
+	}
+
+	return t.supervisor.Start()
+}
+
+// closeTornDown closes the processors and the sources of a torn down topology.
+//
+// The mutex must be held while calling closeTornDown.
+func (t *streamTask) closeTornDown() error {
+	for _, node := range flattenNodeTree(t.sources) {
+		if err := node.Processor().Close(); err != nil {
+			return err
+		}
+	}
+
+	for source, node := range t.sources {
+		if _, ok := t.restartPolicy.Sources[node.Name()]; ok {
+			// Already closed during the teardown.
+			continue
+		}
+
+		if err := source.Close(); err != nil {
+			return err
+		}
+	}
+
+	t.monitor.Close()
+
+	return nil
+}
+
+func (t *streamTask) isClosing() bool {
+	select {
+	case <-t.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+// closeFactorySources closes the sources created by a factory.
+func closeFactorySources(sources map[Source]Node, factories map[string]SourceFactory) {
+	for source, node := range sources {
+		if _, ok := factories[node.Name()]; ok {
+			_ = source.Close()
+		}
+	}
+}
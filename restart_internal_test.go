@@ -0,0 +1,50 @@
+package streams
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestartPolicy_Backoff(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RestartPolicy
+		n      int
+		want   time.Duration
+	}{
+		{
+			name:   "First Restart",
+			policy: RestartPolicy{MinBackoff: time.Second, MaxBackoff: time.Minute},
+			n:      0,
+			want:   time.Second,
+		},
+		{
+			name:   "Doubles",
+			policy: RestartPolicy{MinBackoff: time.Second, MaxBackoff: time.Minute},
+			n:      3,
+			want:   8 * time.Second,
+		},
+		{
+			name:   "Capped",
+			policy: RestartPolicy{MinBackoff: time.Second, MaxBackoff: time.Minute},
+			n:      10,
+			want:   time.Minute,
+		},
+		{
+			name:   "No Maximum",
+			policy: RestartPolicy{MinBackoff: time.Second},
+			n:      10,
+			want:   1024 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.backoff(tt.n)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package streams
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// SignalContext returns a copy of the parent context that is done when a
+// SIGINT or SIGTERM is received, or when the returned cancel function is called.
+//
+// The signals are no longer relayed once the context is done.
+func SignalContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		defer signal.Stop(sigs)
+
+		select {
+		case <-sigs:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// WaitForSignal blocks until a SIGINT or SIGTERM is received, or the context is done.
+func WaitForSignal(ctx context.Context) {
+	ctx, cancel := SignalContext(ctx)
+	defer cancel()
+
+	<-ctx.Done()
+}
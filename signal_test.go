@@ -0,0 +1,59 @@
+package streams_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignalContext(t *testing.T) {
+	ctx, cancel := streams.SignalContext(context.Background())
+	defer cancel()
+
+	err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+	assert.NoError(t, err)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		assert.Fail(t, "context was not cancelled")
+	}
+}
+
+func TestSignalContext_Cancel(t *testing.T) {
+	ctx, cancel := streams.SignalContext(context.Background())
+
+	cancel()
+
+	assert.Error(t, ctx.Err())
+}
+
+func TestWaitForSignal(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		streams.WaitForSignal(context.Background())
+	}()
+
+	// Give the signals time to be relayed before sending one.
+	time.Sleep(10 * time.Millisecond)
+	err := syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+	assert.NoError(t, err)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(t, "did not return on signal")
+	}
+}
+
+func TestWaitForSignal_ContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	streams.WaitForSignal(ctx)
+}
@@ -1,6 +1,12 @@
 package streams
 
+import "context"
+
 // Source represents a stream source.
+//
+// A bounded Source signals the end of the stream by returning io.EOF,
+// after which it will not be consumed from again. A Message returned
+// along with io.EOF is still passed through the topology.
 type Source interface {
 	// Consume gets the next Message from the Source.
 	Consume() (Message, error)
@@ -9,3 +15,41 @@ type Source interface {
 	// Close closes the Source.
 	Close() error
 }
+
+// ContextSource represents a stream source that can block until a Message is available.
+//
+// When a Source implements ContextSource, the source pump uses ConsumeContext
+// in favour of Consume, removing the need to poll for data.
+type ContextSource interface {
+	Source
+
+	// ConsumeContext gets the next Message from the Source, blocking until
+	// a Message is available or the context is done.
+	ConsumeContext(context.Context) (Message, error)
+}
+
+// BatchSource represents a stream source that consumes Messages in batches.
+//
+// When a Source implements BatchSource, the source pump uses ConsumeBatch
+// in favour of Consume, forwarding the batches through the topology.
+type BatchSource interface {
+	Source
+
+	// ConsumeBatch gets the next batch of Messages from the Source.
+	//
+	// An empty batch indicates that there is currently no data available.
+	ConsumeBatch() ([]Message, error)
+}
+
+// ContextBatchSource represents a stream source that can block until a batch of Messages is available.
+//
+// When a BatchSource implements ContextBatchSource, the source pump uses
+// ConsumeBatchContext in favour of ConsumeBatch, so pausing and stopping the
+// pump does not wait for the source to return.
+type ContextBatchSource interface {
+	BatchSource
+
+	// ConsumeBatchContext gets the next batch of Messages from the Source, blocking
+	// until a batch is available or the context is done.
+	ConsumeBatchContext(context.Context) ([]Message, error)
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 
 	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/codec"
 )
 
 // Transaction represents a SQL transaction handler.
@@ -30,6 +31,23 @@ func (fn ExecFunc) Exec(tx *sql.Tx, msg streams.Message) error {
 	return fn(tx, msg)
 }
 
+// SinkOptFunc represents a function that sets up the Sink.
+type SinkOptFunc func(s *Sink)
+
+// WithKeyEncoder sets the encoder used on the Message key before it is executed.
+func WithKeyEncoder(enc codec.Encoder) SinkOptFunc {
+	return func(s *Sink) {
+		s.keyEncoder = enc
+	}
+}
+
+// WithValueEncoder sets the encoder used on the Message value before it is executed.
+func WithValueEncoder(enc codec.Encoder) SinkOptFunc {
+	return func(s *Sink) {
+		s.valueEncoder = enc
+	}
+}
+
 // Sink represents a SQL sink processor.
 type Sink struct {
 	pipe streams.Pipe
@@ -40,12 +58,15 @@ type Sink struct {
 	exec   Executor
 	txHdlr Transaction
 
+	keyEncoder   codec.Encoder
+	valueEncoder codec.Encoder
+
 	batch int
 	count int
 }
 
 // NewSink creates a new batch sql insert sink.
-func NewSink(db *sql.DB, batch int, exec Executor) (*Sink, error) {
+func NewSink(db *sql.DB, batch int, exec Executor, opts ...SinkOptFunc) (*Sink, error) {
 	s := &Sink{
 		db:    db,
 		exec:  exec,
@@ -53,6 +74,10 @@ func NewSink(db *sql.DB, batch int, exec Executor) (*Sink, error) {
 		count: 0,
 	}
 
+	for _, optFn := range opts {
+		optFn(s)
+	}
+
 	if txHdlr, ok := exec.(Transaction); ok {
 		s.txHdlr = txHdlr
 	}
@@ -75,7 +100,12 @@ func (p *Sink) Process(msg streams.Message) error {
 		return err
 	}
 
-	if err := p.exec.Exec(p.tx, msg); err != nil {
+	encoded, err := codec.EncodeMessage(msg, p.keyEncoder, p.valueEncoder)
+	if err != nil {
+		return err
+	}
+
+	if err := p.exec.Exec(p.tx, encoded); err != nil {
 		return err
 	}
 
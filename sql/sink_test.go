@@ -8,6 +8,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/codec"
 	"github.com/rafalmnich/streams/v6/mocks"
 	sqlx "github.com/rafalmnich/streams/v6/sql"
 	"github.com/stretchr/testify/assert"
@@ -100,6 +101,45 @@ func TestSink_Process(t *testing.T) {
 	}
 }
 
+func TestSink_ProcessWithEncoders(t *testing.T) {
+	db, dbMock := newDB(t)
+	defer db.Close()
+	dbMock.ExpectBegin()
+
+	exec := new(MockExecutor)
+	exec.On("Exec", mock.Anything, mock.Anything).Return(nil)
+
+	pipe := mocks.NewPipe(t)
+	pipe.ExpectMark("test", "test")
+
+	s, _ := sqlx.NewSink(db, 10, exec, sqlx.WithKeyEncoder(codec.StringEncoder{}), sqlx.WithValueEncoder(codec.StringEncoder{}))
+	s.WithPipe(pipe)
+
+	err := s.Process(streams.NewMessage("test", "test"))
+
+	assert.NoError(t, err)
+	exec.AssertCalled(t, "Exec", mock.Anything, streams.NewMessage([]byte("test"), []byte("test")))
+}
+
+func TestSink_ProcessWithEncoderError(t *testing.T) {
+	db, dbMock := newDB(t)
+	defer db.Close()
+	dbMock.ExpectBegin()
+
+	exec := new(MockExecutor)
+	enc := codec.EncoderFunc(func(interface{}) ([]byte, error) {
+		return nil, errors.New("test")
+	})
+
+	s, _ := sqlx.NewSink(db, 10, exec, sqlx.WithValueEncoder(enc))
+	s.WithPipe(mocks.NewPipe(t))
+
+	err := s.Process(streams.NewMessage("test", "test"))
+
+	assert.Error(t, err)
+	exec.AssertNotCalled(t, "Exec", mock.Anything, mock.Anything)
+}
+
 func TestSink_ProcessWithTxError(t *testing.T) {
 	db, dbMock := newDB(t)
 	defer db.Close()
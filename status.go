@@ -0,0 +1,176 @@
+package streams
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// TaskState represents the state of a Task.
+type TaskState int32
+
+// TaskState types.
+const (
+	// StateIdle is the state of a Task that has not been started.
+	StateIdle TaskState = iota
+	// StateRunning is the state of a Task that is consuming from its sources.
+	StateRunning
+	// StatePaused is the state of a paused Task.
+	StatePaused
+	// StateRestarting is the state of a Task that is being restarted after an error.
+	StateRestarting
+	// StateDraining is the state of a Task that is processing the messages in flight before closing.
+	StateDraining
+	// StateStopped is the state of a Task that has finished without an error.
+	StateStopped
+	// StateFailed is the state of a Task that has finished with an error.
+	StateFailed
+)
+
+var stateNames = map[TaskState]string{
+	StateIdle:       "idle",
+	StateRunning:    "running",
+	StatePaused:     "paused",
+	StateRestarting: "restarting",
+	StateDraining:   "draining",
+	StateStopped:    "stopped",
+	StateFailed:     "failed",
+}
+
+// String returns the name of the state.
+func (s TaskState) String() string {
+	if name, ok := stateNames[s]; ok {
+		return name
+	}
+
+	return "unknown"
+}
+
+// MarshalText encodes the state as its name.
+func (s TaskState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// NodeStatus represents the status of a processor node in a Task.
+type NodeStatus struct {
+	// Name is the name of the node.
+	Name string
+	// QueueDepth is the number of items queued in the pump of the node.
+	QueueDepth int
+	// QueueCapacity is the capacity of the pump queue. It is 0 for synchronous pumps.
+	QueueCapacity int
+}
+
+// TaskStatus represents a snapshot of the status of a Task.
+type TaskStatus struct {
+	// State is the state of the Task.
+	State TaskState
+	// Nodes is the status of the processor nodes, ordered by name.
+	Nodes []NodeStatus
+	// LastCommit is the time of the last successful commit, if any.
+	LastCommit time.Time
+	// LastError is the last error raised by the Task, if any.
+	LastError error
+	// Restarts is the number of restarts of the Task.
+	Restarts int
+}
+
+type nodeStatusJSON struct {
+	Name          string `json:"name"`
+	QueueDepth    int    `json:"queueDepth"`
+	QueueCapacity int    `json:"queueCapacity"`
+}
+
+type taskStatusJSON struct {
+	State      TaskState        `json:"state"`
+	Nodes      []nodeStatusJSON `json:"nodes"`
+	LastCommit *time.Time       `json:"lastCommit,omitempty"`
+	LastError  string           `json:"lastError,omitempty"`
+	Restarts   int              `json:"restarts"`
+}
+
+// MarshalJSON encodes the status as JSON.
+func (s TaskStatus) MarshalJSON() ([]byte, error) {
+	v := taskStatusJSON{
+		State:    s.State,
+		Nodes:    make([]nodeStatusJSON, 0, len(s.Nodes)),
+		Restarts: s.Restarts,
+	}
+
+	for _, n := range s.Nodes {
+		v.Nodes = append(v.Nodes, nodeStatusJSON(n))
+	}
+
+	if !s.LastCommit.IsZero() {
+		v.LastCommit = &s.LastCommit
+	}
+
+	if s.LastError != nil {
+		v.LastError = s.LastError.Error()
+	}
+
+	return json.Marshal(v)
+}
+
+// topologyView represents the parts of a running topology the status is built from.
+type topologyView struct {
+	supervisor Supervisor
+	pumps      map[Node]Pump
+}
+
+// taskError wraps an error, so it can be stored in an atomic.Value.
+type taskError struct {
+	err error
+}
+
+// nodeStatuses returns the status of the nodes, ordered by name.
+func nodeStatuses(pumps map[Node]Pump) []NodeStatus {
+	statuses := make([]NodeStatus, 0, len(pumps))
+	for node, pump := range pumps {
+		status := NodeStatus{Name: node.Name()}
+		if q, ok := pump.(queue); ok {
+			status.QueueDepth, status.QueueCapacity = q.queueDepth()
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses
+}
+
+type statusHandler struct {
+	task ManagedTask
+}
+
+// NewStatusHandler returns an http.Handler that exposes the status of the Task as JSON.
+//
+// The handler responds with 200 OK while the task is running or paused, and with
+// 503 Service Unavailable otherwise, so it can be used for readiness probes.
+func NewStatusHandler(task ManagedTask) http.Handler {
+	return &statusHandler{task: task}
+}
+
+// ServeHTTP responds with the status of the Task.
+func (h *statusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status := h.task.Status()
+
+	b, err := json.Marshal(status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	code := http.StatusOK
+	if status.State != StateRunning && status.State != StatePaused {
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(b)
+}
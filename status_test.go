@@ -0,0 +1,161 @@
+package streams_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskState_String(t *testing.T) {
+	tests := []struct {
+		state streams.TaskState
+		want  string
+	}{
+		{state: streams.StateIdle, want: "idle"},
+		{state: streams.StateRunning, want: "running"},
+		{state: streams.StatePaused, want: "paused"},
+		{state: streams.StateRestarting, want: "restarting"},
+		{state: streams.StateDraining, want: "draining"},
+		{state: streams.StateStopped, want: "stopped"},
+		{state: streams.StateFailed, want: "failed"},
+		{state: streams.TaskState(100), want: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.state.String())
+		})
+	}
+}
+
+func TestTaskStatus_MarshalJSON(t *testing.T) {
+	status := streams.TaskStatus{
+		State:      streams.StateRunning,
+		Nodes:      []streams.NodeStatus{{Name: "test", QueueDepth: 1, QueueCapacity: 10}},
+		LastCommit: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		LastError:  errors.New("test error"),
+		Restarts:   2,
+	}
+
+	b, err := json.Marshal(status)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"state": "running",
+		"nodes": [{"name": "test", "queueDepth": 1, "queueCapacity": 10}],
+		"lastCommit": "2020-01-02T03:04:05Z",
+		"lastError": "test error",
+		"restarts": 2
+	}`, string(b))
+}
+
+func TestTaskStatus_MarshalJSONOmitsEmpty(t *testing.T) {
+	b, err := json.Marshal(streams.TaskStatus{})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"state": "idle", "nodes": [], "restarts": 0}`, string(b))
+}
+
+func TestStreamTask_Status(t *testing.T) {
+	msgs := make(chan streams.Message)
+
+	b := streams.NewStreamBuilder()
+	b.Source("src", &chanSource{msgs: msgs}).
+		MapFunc("pass-through", passThroughMapper, streams.WithBufferSize(10)).
+		Process("processor", newRecordingProcessor())
+
+	tp, _ := b.Build()
+	task := streams.NewTask(tp)
+	task.OnError(func(err error) {
+		t.FailNow()
+	})
+
+	assert.Equal(t, streams.StateIdle, task.Status().State)
+
+	err := task.Start(context.Background())
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	status := task.Status()
+	assert.Equal(t, streams.StateRunning, status.State)
+	assert.Equal(t, []streams.NodeStatus{
+		{Name: "pass-through", QueueCapacity: 10},
+		{Name: "processor", QueueCapacity: 1000},
+	}, status.Nodes)
+	assert.True(t, status.LastCommit.IsZero())
+
+	_ = task.Pause()
+	assert.Equal(t, streams.StatePaused, task.Status().State)
+
+	_ = task.Resume()
+	assert.Equal(t, streams.StateRunning, task.Status().State)
+
+	_ = task.Close()
+
+	status = task.Status()
+	assert.Equal(t, streams.StateStopped, status.State)
+	assert.False(t, status.LastCommit.IsZero())
+	assert.NoError(t, status.LastError)
+}
+
+func TestStreamTask_StatusFailed(t *testing.T) {
+	source := new(MockSource)
+	source.On("Consume").Return(streams.Message{}, errors.New("test error"))
+	source.On("Close").Return(nil)
+
+	b := streams.NewStreamBuilder()
+	b.Source("src", source)
+
+	tp, _ := b.Build()
+	task := streams.NewTask(tp)
+	task.OnError(func(err error) {})
+
+	err := task.Start(context.Background())
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	defer task.Close()
+
+	_ = task.Wait()
+
+	status := task.Status()
+	assert.Equal(t, streams.StateFailed, status.State)
+	assert.EqualError(t, status.LastError, "test error")
+}
+
+func TestStatusHandler(t *testing.T) {
+	tests := []struct {
+		name  string
+		state streams.TaskState
+		code  int
+	}{
+		{name: "Running", state: streams.StateRunning, code: http.StatusOK},
+		{name: "Paused", state: streams.StatePaused, code: http.StatusOK},
+		{name: "Idle", state: streams.StateIdle, code: http.StatusServiceUnavailable},
+		{name: "Draining", state: streams.StateDraining, code: http.StatusServiceUnavailable},
+		{name: "Failed", state: streams.StateFailed, code: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := new(MockTask)
+			task.On("Status").Return(streams.TaskStatus{State: tt.state})
+			h := streams.NewStatusHandler(task)
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.JSONEq(t, `{"state": "`+tt.state.String()+`", "nodes": [], "restarts": 0}`, rec.Body.String())
+		})
+	}
+}
@@ -19,6 +19,11 @@ func (sb *StreamBuilder) Source(name string, source Source) *Stream {
 	return newStream(sb.tp, []Node{n})
 }
 
+// AddInspection adds an Inspection that is performed on the topology when it is built.
+func (sb *StreamBuilder) AddInspection(fn Inspection) {
+	sb.tp.AddInspection(fn)
+}
+
 // Build builds the stream Topology.
 func (sb *StreamBuilder) Build() (*Topology, []error) {
 	return sb.tp.Build()
@@ -38,16 +43,16 @@ func newStream(tp *TopologyBuilder, parents []Node) *Stream {
 }
 
 // Filter filters the stream using a predicate.
-func (s *Stream) Filter(name string, pred Predicate) *Stream {
+func (s *Stream) Filter(name string, pred Predicate, opts ...PumpOptFunc) *Stream {
 	p := NewFilterProcessor(pred)
-	n := s.tp.AddProcessor(name, p, s.parents)
+	n := s.tp.AddProcessor(name, p, s.parents, opts...)
 
 	return newStream(s.tp, []Node{n})
 }
 
 // FilterFunc filters the stream using a predicate.
-func (s *Stream) FilterFunc(name string, pred PredicateFunc) *Stream {
-	return s.Filter(name, pred)
+func (s *Stream) FilterFunc(name string, pred PredicateFunc, opts ...PumpOptFunc) *Stream {
+	return s.Filter(name, pred, opts...)
 }
 
 // Branch branches a stream based on the given predicates.
@@ -73,29 +78,59 @@ func (s *Stream) BranchFunc(name string, preds ...PredicateFunc) []*Stream {
 }
 
 // Map runs a mapper on the stream.
-func (s *Stream) Map(name string, mapper Mapper) *Stream {
+func (s *Stream) Map(name string, mapper Mapper, opts ...PumpOptFunc) *Stream {
 	p := NewMapProcessor(mapper)
-	n := s.tp.AddProcessor(name, p, s.parents)
+	n := s.tp.AddProcessor(name, p, s.parents, opts...)
 
 	return newStream(s.tp, []Node{n})
 }
 
 // MapFunc runs a mapper on the stream.
-func (s *Stream) MapFunc(name string, mapper MapperFunc) *Stream {
-	return s.Map(name, mapper)
+func (s *Stream) MapFunc(name string, mapper MapperFunc, opts ...PumpOptFunc) *Stream {
+	return s.Map(name, mapper, opts...)
+}
+
+// AsyncMap runs a mapper on the stream, mapping up to concurrency messages at once.
+//
+// The mapped messages are forwarded in the order they were received.
+func (s *Stream) AsyncMap(name string, mapper Mapper, concurrency int, opts ...PumpOptFunc) *Stream {
+	p := NewAsyncMapProcessor(mapper, concurrency)
+	n := s.tp.AddProcessor(name, p, s.parents, opts...)
+
+	return newStream(s.tp, []Node{n})
+}
+
+// AsyncMapFunc runs a mapper on the stream, mapping up to concurrency messages at once.
+func (s *Stream) AsyncMapFunc(name string, mapper MapperFunc, concurrency int, opts ...PumpOptFunc) *Stream {
+	return s.AsyncMap(name, mapper, concurrency, opts...)
+}
+
+// AsyncMapUnordered runs a mapper on the stream, mapping up to concurrency messages at once.
+//
+// The mapped messages are forwarded as soon as they are mapped.
+func (s *Stream) AsyncMapUnordered(name string, mapper Mapper, concurrency int, opts ...PumpOptFunc) *Stream {
+	p := NewAsyncMapUnorderedProcessor(mapper, concurrency)
+	n := s.tp.AddProcessor(name, p, s.parents, opts...)
+
+	return newStream(s.tp, []Node{n})
+}
+
+// AsyncMapUnorderedFunc runs a mapper on the stream, mapping up to concurrency messages at once.
+func (s *Stream) AsyncMapUnorderedFunc(name string, mapper MapperFunc, concurrency int, opts ...PumpOptFunc) *Stream {
+	return s.AsyncMapUnordered(name, mapper, concurrency, opts...)
 }
 
 // FlatMap runs a flat mapper on the stream.
-func (s *Stream) FlatMap(name string, mapper FlatMapper) *Stream {
+func (s *Stream) FlatMap(name string, mapper FlatMapper, opts ...PumpOptFunc) *Stream {
 	p := NewFlatMapProcessor(mapper)
-	n := s.tp.AddProcessor(name, p, s.parents)
+	n := s.tp.AddProcessor(name, p, s.parents, opts...)
 
 	return newStream(s.tp, []Node{n})
 }
 
 // FlatMapFunc runs a flat mapper on the stream.
-func (s *Stream) FlatMapFunc(name string, mapper FlatMapperFunc) *Stream {
-	return s.FlatMap(name, mapper)
+func (s *Stream) FlatMapFunc(name string, mapper FlatMapperFunc, opts ...PumpOptFunc) *Stream {
+	return s.FlatMap(name, mapper, opts...)
 }
 
 // Merge merges one or more streams into this stream.
@@ -119,8 +154,10 @@ func (s *Stream) Print(name string) *Stream {
 }
 
 // Process runs a custom processor on the stream.
-func (s *Stream) Process(name string, p Processor) *Stream {
-	n := s.tp.AddProcessor(name, p, s.parents)
+//
+// The pump options configure the asynchronous pump of the processor.
+func (s *Stream) Process(name string, p Processor, opts ...PumpOptFunc) *Stream {
+	n := s.tp.AddProcessor(name, p, s.parents, opts...)
 
 	return newStream(s.tp, []Node{n})
 }
@@ -1,6 +1,7 @@
 package streams
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -31,6 +32,17 @@ func TestStreamBuilder_Build(t *testing.T) {
 	assert.Contains(t, top.Sources(), source)
 }
 
+func TestStreamBuilder_AddInspection(t *testing.T) {
+	builder := NewStreamBuilder()
+	builder.AddInspection(func(map[Source]Node, []Node) error {
+		return errors.New("test error")
+	})
+
+	_, errs := builder.Build()
+
+	assert.Equal(t, []error{errors.New("test error")}, errs)
+}
+
 func TestStream_Filter(t *testing.T) {
 	source := &streamSource{}
 	builder := NewStreamBuilder()
@@ -126,6 +138,19 @@ func TestStream_Map(t *testing.T) {
 	assert.IsType(t, &MapProcessor{}, stream.parents[0].(*ProcessorNode).processor)
 }
 
+func TestStream_MapWithPumpOpts(t *testing.T) {
+	source := &streamSource{}
+	builder := NewStreamBuilder()
+
+	stream := builder.Source("source", source).
+		MapFunc("test", func(msg Message) (Message, error) {
+			return EmptyMessage, nil
+		}, WithWorkers(4))
+
+	assert.Len(t, stream.parents, 1)
+	assert.Len(t, stream.parents[0].(*ProcessorNode).pumpOpts, 1)
+}
+
 func TestStream_MapFunc(t *testing.T) {
 	source := &streamSource{}
 	builder := NewStreamBuilder()
@@ -141,6 +166,36 @@ func TestStream_MapFunc(t *testing.T) {
 	assert.IsType(t, &MapProcessor{}, stream.parents[0].(*ProcessorNode).processor)
 }
 
+func TestStream_AsyncMap(t *testing.T) {
+	source := &streamSource{}
+	builder := NewStreamBuilder()
+
+	stream := builder.Source("source", source).
+		AsyncMapFunc("test", func(msg Message) (Message, error) {
+			return EmptyMessage, nil
+		}, 10)
+
+	assert.Len(t, stream.parents, 1)
+	assert.IsType(t, &ProcessorNode{}, stream.parents[0])
+	assert.Equal(t, stream.parents[0].(*ProcessorNode).name, "test")
+	assert.IsType(t, &AsyncMapProcessor{}, stream.parents[0].(*ProcessorNode).processor)
+	assert.True(t, stream.parents[0].(*ProcessorNode).processor.(*AsyncMapProcessor).ordered)
+}
+
+func TestStream_AsyncMapUnordered(t *testing.T) {
+	source := &streamSource{}
+	builder := NewStreamBuilder()
+
+	stream := builder.Source("source", source).
+		AsyncMapUnorderedFunc("test", func(msg Message) (Message, error) {
+			return EmptyMessage, nil
+		}, 10)
+
+	assert.Len(t, stream.parents, 1)
+	assert.IsType(t, &AsyncMapProcessor{}, stream.parents[0].(*ProcessorNode).processor)
+	assert.False(t, stream.parents[0].(*ProcessorNode).processor.(*AsyncMapProcessor).ordered)
+}
+
 func TestStream_FlatMap(t *testing.T) {
 	source := &streamSource{}
 	builder := NewStreamBuilder()
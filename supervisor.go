@@ -62,7 +62,16 @@ type Supervisor interface {
 	Commit(Processor) error
 }
 
+// commitTracker represents a supervisor that tracks its commits.
+type commitTracker interface {
+	// lastCommit returns the time of the last successful commit.
+	lastCommit() time.Time
+}
+
 type supervisor struct {
+	// committed is accessed atomically and must be 64-bit aligned.
+	committed int64
+
 	store    Metastore
 	strategy MetadataStrategy
 
@@ -157,9 +166,21 @@ func (s *supervisor) Commit(caller Processor) error {
 	latency := time.Duration(nanotime() - start)
 	s.mon.Committed(latency)
 
+	atomic.StoreInt64(&s.committed, time.Now().UnixNano())
+
 	return nil
 }
 
+// lastCommit returns the time of the last successful commit.
+func (s *supervisor) lastCommit() time.Time {
+	committed := atomic.LoadInt64(&s.committed)
+	if committed == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, committed)
+}
+
 func (s *supervisor) commit(caller Processor, comm Committer) (Metaitems, error) {
 	locker, err := s.getLocker(caller, comm)
 	if err != nil {
@@ -204,6 +225,7 @@ type timedSupervisor struct {
 	errFn ErrorFunc
 
 	t       *time.Ticker
+	done    chan struct{}
 	commits uint32
 	running uint32
 }
@@ -242,23 +264,32 @@ func (s *timedSupervisor) Start() error {
 	}
 
 	s.t = time.NewTicker(s.d)
+	s.done = make(chan struct{})
 
-	go func() {
-		for range s.t.C {
-			// If there was a commit triggered "manually" by a Committer, skip a single timed commit.
-			if atomic.LoadUint32(&s.commits) > 0 {
-				atomic.StoreUint32(&s.commits, 0)
-				continue
-			}
+	go s.run(s.t, s.done)
 
-			err := s.inner.Commit(nil)
-			if err != nil {
-				s.errFn(err)
-			}
+	return s.inner.Start()
+}
+
+func (s *timedSupervisor) run(t *time.Ticker, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
 		}
-	}()
 
-	return s.inner.Start()
+		// If there was a commit triggered "manually" by a Committer, skip a single timed commit.
+		if atomic.LoadUint32(&s.commits) > 0 {
+			atomic.StoreUint32(&s.commits, 0)
+			continue
+		}
+
+		err := s.inner.Commit(nil)
+		if err != nil {
+			s.errFn(err)
+		}
+	}
 }
 
 // Close stops the timer and closes the inner supervisor.
@@ -268,6 +299,7 @@ func (s *timedSupervisor) Close() error {
 	}
 
 	s.t.Stop()
+	close(s.done)
 
 	return s.inner.Close()
 }
@@ -291,6 +323,15 @@ func (s *timedSupervisor) Commit(caller Processor) error {
 	return nil
 }
 
+// lastCommit returns the time of the last successful commit of the inner supervisor.
+func (s *timedSupervisor) lastCommit() time.Time {
+	if ct, ok := s.inner.(commitTracker); ok {
+		return ct.lastCommit()
+	}
+
+	return time.Time{}
+}
+
 func (s *timedSupervisor) setRunning() bool {
 	return atomic.CompareAndSwapUint32(&s.running, stopped, running)
 }
@@ -63,6 +63,8 @@ type fakeMonitor struct{}
 
 func (*fakeMonitor) Processed(name string, l time.Duration, bp float64) {}
 
+func (*fakeMonitor) Failed(name string) {}
+
 func (*fakeMonitor) Committed(l time.Duration) {}
 
 func (*fakeMonitor) Close() error {
@@ -3,6 +3,7 @@ package streams_test
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -298,9 +299,11 @@ func TestTimedSupervisor_Start(t *testing.T) {
 	inner := new(MockSupervisor)
 	inner.On("Commit", nil).Return(nil)
 	inner.On("Start").Return(wantErr)
+	inner.On("Close").Return(nil)
 
 	supervisor := streams.NewTimedSupervisor(inner, 1, nil)
 	err := supervisor.Start()
+	defer supervisor.Close()
 
 	inner.AssertCalled(t, "Start")
 	assert.Equal(t, wantErr, err)
@@ -312,10 +315,10 @@ func TestTimedSupervisor_GlobalCommitSourceError(t *testing.T) {
 	inner.On("Commit", nil).Return(errors.New("error"))
 	inner.On("Close").Return(nil)
 
-	called := false
+	var called int32
 	supervisor := streams.NewTimedSupervisor(inner, time.Millisecond, func(err error) {
 		assert.Equal(t, "error", err.Error())
-		called = true
+		atomic.StoreInt32(&called, 1)
 	})
 	_ = supervisor.Start()
 	defer supervisor.Close()
@@ -323,7 +326,7 @@ func TestTimedSupervisor_GlobalCommitSourceError(t *testing.T) {
 	time.Sleep(8 * time.Millisecond)
 
 	inner.AssertCalled(t, "Commit", nil)
-	assert.True(t, called, "Expected error function to be called")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&called), "Expected error function to be called")
 }
 
 func TestTimedSupervisor_Start_AlreadyRunning(t *testing.T) {
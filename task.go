@@ -3,6 +3,8 @@ package streams
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -55,6 +57,13 @@ func WithMonitorInterval(d time.Duration) TaskOptFunc {
 	}
 }
 
+// WithRestartPolicy defines the policy of restarting the task on errors.
+func WithRestartPolicy(policy RestartPolicy) TaskOptFunc {
+	return func(t *streamTask) {
+		t.restartPolicy = &policy
+	}
+}
+
 // WithStats sets the stats handler.
 func WithStats(stats Stats) TaskOptFunc {
 	return func(t *streamTask) {
@@ -72,20 +81,48 @@ type Task interface {
 	Close() error
 }
 
+// ManagedTask represents a streams task that can be paused, gracefully
+// shut down and observed while running.
+type ManagedTask interface {
+	Task
+
+	// Pause stops consuming from the sources, without closing them.
+	Pause() error
+	// Resume resumes consuming from the sources after a Pause.
+	Resume() error
+	// Shutdown gracefully stops and closes the streams processors,
+	// aborting when the context is done.
+	Shutdown(ctx context.Context) error
+	// Done returns a channel that is closed when the task has finished, either
+	// because all of its sources are exhausted, it failed or it was closed.
+	Done() <-chan struct{}
+	// Wait blocks until the task has finished, returning the error that stopped it, if any.
+	Wait() error
+	// Status returns a snapshot of the status of the task.
+	Status() TaskStatus
+}
+
 type supervisorOpts struct {
 	Strategy MetadataStrategy
 	Interval time.Duration
 }
 
 type streamTask struct {
+	// rebuiltAt is the time of the last rebuild of the topology, in nanoseconds.
+	// It is accessed atomically and kept first for 64-bit alignment.
+	rebuiltAt int64
+
 	topology *Topology
+	sources  map[Source]Node
 
-	running         bool
+	running         int32
+	paused          bool
 	mode            TaskMode
 	monitorInterval time.Duration
 	errorFn         ErrorFunc
 
-	stats Stats
+	stats  Stats
+	tracer Tracer
 
 	store          Metastore
 	supervisorOpts supervisorOpts
@@ -93,10 +130,31 @@ type streamTask struct {
 	monitor        Monitor
 	srcPumps       SourcePumps
 	pumps          map[Node]Pump
+
+	// mu guards the setup and teardown of the topology.
+	mu            sync.Mutex
+	ctx           context.Context
+	restartPolicy *RestartPolicy
+	restarts      int32
+	restarting    int32
+	gen           uint32
+	torndown      bool
+	closed        bool
+	closing       chan struct{}
+	closeOnce     sync.Once
+	stop          chan struct{}
+
+	done     chan struct{}
+	doneOnce sync.Once
+	err      error
+
+	state   int32
+	view    atomic.Value // *topologyView
+	lastErr atomic.Value // taskError
 }
 
 // NewTask creates a new streams task.
-func NewTask(topology *Topology, opts ...TaskOptFunc) Task {
+func NewTask(topology *Topology, opts ...TaskOptFunc) ManagedTask {
 	store := NewMetastore()
 
 	t := &streamTask{
@@ -112,62 +170,202 @@ func NewTask(topology *Topology, opts ...TaskOptFunc) Task {
 		},
 		srcPumps: SourcePumps{},
 		pumps:    map[Node]Pump{},
+		closing:  make(chan struct{}),
+		done:     make(chan struct{}),
 	}
 
 	for _, optFn := range opts {
 		optFn(t)
 	}
 
-	t.supervisor = NewSupervisor(t.store, t.supervisorOpts.Strategy)
+	t.supervisor = t.newSupervisor()
+
+	return t
+}
+
+func (t *streamTask) newSupervisor() Supervisor {
+	supervisor := NewSupervisor(t.store, t.supervisorOpts.Strategy)
 	if t.supervisorOpts.Interval > 0 {
-		t.supervisor = NewTimedSupervisor(t.supervisor, t.supervisorOpts.Interval, t.handleError)
+		supervisor = NewTimedSupervisor(supervisor, t.supervisorOpts.Interval, t.errorHandler())
 	}
 
-	return t
+	return supervisor
 }
 
 // Start starts the streams processors.
 func (t *streamTask) Start(ctx context.Context) error {
 	// If we are already running, exit
-	if t.running {
+	if !atomic.CompareAndSwapInt32(&t.running, 0, 1) {
 		return errors.New("streams: task already running")
 	}
-	t.running = true
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.ctx = ctx
+	t.sources = make(map[Source]Node, len(t.topology.Sources()))
+	for source, node := range t.topology.Sources() {
+		t.sources[source] = node
+	}
+	t.monitor = NewMonitor(t.stats, t.monitorInterval)
 
 	t.setupTopology(ctx)
+	t.setState(StateRunning)
 
 	return t.supervisor.Start()
 }
 
 func (t *streamTask) setupTopology(ctx context.Context) {
-	t.monitor = NewMonitor(t.stats, t.monitorInterval)
+	errFn := t.errorHandler()
 
-	nodes := flattenNodeTree(t.topology.Sources())
+	nodes := flattenNodeTree(t.sources)
 	reverseNodes(nodes)
 	for _, node := range nodes {
 		pipe := NewPipe(t.store, t.supervisor, node.Processor(), t.resolvePumps(node.Children()))
 		node.Processor().WithPipe(pipe)
 
-		pump := t.newPump(t.monitor, node, pipe.(TimedPipe), t.handleError)
+		pump := t.newPump(t.monitor, node, pipe.(TimedPipe), errFn)
 		t.pumps[node] = pump
 	}
 
 	t.supervisor.WithPumps(t.pumps)
 	t.supervisor.WithContext(ctx)
 	t.supervisor.WithMonitor(t.monitor)
+	t.view.Store(&topologyView{supervisor: t.supervisor, pumps: t.pumps})
 
-	for source, node := range t.topology.Sources() {
-		srcPump := NewSourcePump(t.monitor, node.Name(), source, t.resolvePumps(node.Children()), t.handleError)
+	for source, node := range t.sources {
+		srcPump := newSourcePump(ctx, t.monitor, node.Name(), source, t.resolvePumps(node.Children()), errFn)
 		t.srcPumps = append(t.srcPumps, srcPump)
 	}
+
+	if len(t.srcPumps) > 0 {
+		t.stop = make(chan struct{})
+		go t.watchSources(atomic.LoadUint32(&t.gen), t.srcPumps, t.stop)
+	}
+}
+
+// watchSources completes the task once all of the sources are exhausted.
+func (t *streamTask) watchSources(gen uint32, srcPumps SourcePumps, stop chan struct{}) {
+	for _, srcPump := range srcPumps {
+		select {
+		case <-srcPump.Done():
+		case <-stop:
+			return
+		}
+	}
+
+	t.complete(gen)
+}
+
+// complete drains the topology, commits and closes it, finishing the task.
+func (t *streamTask) complete(gen uint32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if atomic.LoadUint32(&t.gen) != gen || t.closed {
+		return
+	}
+	t.closed = true
+	t.stop = nil
+	atomic.StoreInt32(&t.running, 0)
+	t.setState(StateDraining)
+
+	t.srcPumps.StopAll()
+	t.stopPumps()
+
+	t.finish(t.closeTopology())
+}
+
+// stopWatching stops watching the sources for completion.
+//
+// The mutex must be held while calling stopWatching.
+func (t *streamTask) stopWatching() {
+	if t.stop == nil {
+		return
+	}
+
+	close(t.stop)
+	t.stop = nil
+}
+
+// finish marks the task as finished with the given error.
+func (t *streamTask) finish(err error) {
+	t.doneOnce.Do(func() {
+		t.err = err
+
+		if err != nil {
+			t.setState(StateFailed)
+		} else {
+			t.setState(StateStopped)
+		}
+
+		if t.done != nil {
+			close(t.done)
+		}
+	})
+}
+
+// Done returns a channel that is closed when the task has finished, either
+// because all of its sources are exhausted, it failed or it was closed.
+func (t *streamTask) Done() <-chan struct{} {
+	return t.done
+}
+
+// Wait blocks until the task has finished, returning the error that stopped it, if any.
+func (t *streamTask) Wait() error {
+	<-t.done
+
+	return t.err
+}
+
+// setState sets the state of the task. A failed task remains failed.
+func (t *streamTask) setState(state TaskState) {
+	for {
+		old := atomic.LoadInt32(&t.state)
+		if TaskState(old) == StateFailed || atomic.CompareAndSwapInt32(&t.state, old, int32(state)) {
+			return
+		}
+	}
+}
+
+// Status returns a snapshot of the status of the task.
+func (t *streamTask) Status() TaskStatus {
+	status := TaskStatus{
+		State:    TaskState(atomic.LoadInt32(&t.state)),
+		Restarts: int(atomic.LoadInt32(&t.restarts)),
+	}
+
+	if e, ok := t.lastErr.Load().(taskError); ok {
+		status.LastError = e.err
+	}
+
+	view, ok := t.view.Load().(*topologyView)
+	if !ok {
+		return status
+	}
+
+	if ct, ok := view.supervisor.(commitTracker); ok {
+		status.LastCommit = ct.lastCommit()
+	}
+	status.Nodes = nodeStatuses(view.pumps)
+
+	return status
 }
 
 func (t *streamTask) newPump(mon Monitor, node Node, pipe TimedPipe, errFn ErrorFunc) Pump {
+	var opts []PumpOptFunc
+	if n, ok := node.(*ProcessorNode); ok {
+		opts = append(opts, n.PumpOpts()...)
+	}
+	if t.tracer != nil {
+		opts = append(opts, WithPumpTracer(t.tracer))
+	}
+
 	if t.mode == Sync {
-		return NewSyncPump(mon, node, pipe)
+		return NewSyncPump(mon, node, pipe, opts...)
 	}
 
-	return NewAsyncPump(mon, node, pipe, errFn)
+	return NewAsyncPump(mon, node, pipe, errFn, opts...)
 }
 
 func (t *streamTask) resolvePumps(nodes []Node) []Pump {
@@ -178,20 +376,117 @@ func (t *streamTask) resolvePumps(nodes []Node) []Pump {
 	return pumps
 }
 
-// Close stops and closes the streams processors.
-func (t *streamTask) Close() error {
-	t.running = false
-	t.srcPumps.StopAll()
+// Pause stops consuming from the sources, without closing them.
+//
+// Messages already consumed continue to flow through the processors.
+func (t *streamTask) Pause() error {
+	if atomic.LoadInt32(&t.running) == 0 {
+		return errors.New("streams: task not running")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.paused {
+		return nil
+	}
+	t.paused = true
+
+	t.srcPumps.PauseAll()
+	t.setState(StatePaused)
+
+	return nil
+}
+
+// Resume resumes consuming from the sources after a Pause.
+func (t *streamTask) Resume() error {
+	if atomic.LoadInt32(&t.running) == 0 {
+		return errors.New("streams: task not running")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.paused {
+		return nil
+	}
+	t.paused = false
+
+	t.srcPumps.ResumeAll()
+	t.setState(StateRunning)
+
+	return nil
+}
+
+// Shutdown gracefully stops and closes the streams processors.
+//
+// Shutdown stops consuming from the sources, waits for the messages in flight
+// to be processed, commits the committers and closes the processors and sources.
+// If the context is done before the messages in flight are processed, nothing is
+// committed, the sources are closed and the context error is returned.
+func (t *streamTask) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&t.running, 0)
+	t.closeOnce.Do(func() { close(t.closing) })
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	t.stopWatching()
+
+	t.setState(StateDraining)
+
+	var err error
+	if t.torndown {
+		err = t.closeTornDown()
+	} else {
+		err = t.shutdownTopology(ctx)
+	}
+
+	t.finish(err)
+
+	return err
+}
+
+// shutdownTopology drains, commits and closes the topology, aborting when the context is done.
+func (t *streamTask) shutdownTopology(ctx context.Context) error {
+	drained := make(chan struct{})
+	go func() {
+		t.srcPumps.StopAll()
+		t.stopPumps()
+
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		t.abortTopology(drained)
+
+		return ctx.Err()
+	}
 
 	return t.closeTopology()
 }
 
-func (t *streamTask) closeTopology() error {
-	// Stop the pumps
-	nodes := flattenNodeTree(t.topology.Sources())
+// Close stops and closes the streams processors.
+func (t *streamTask) Close() error {
+	return t.Shutdown(context.Background())
+}
+
+// stopPumps stops the pumps, waiting for the messages in flight to be processed.
+func (t *streamTask) stopPumps() {
+	nodes := flattenNodeTree(t.sources)
 	for _, node := range nodes {
 		t.pumps[node].Stop()
 	}
+}
+
+func (t *streamTask) closeTopology() error {
+	nodes := flattenNodeTree(t.sources)
 
 	// Commit any outstanding batches and metadata
 	if err := t.supervisor.Commit(nil); err != nil {
@@ -222,10 +517,64 @@ func (t *streamTask) closeTopology() error {
 	return nil
 }
 
+// abortTopology closes the supervisor and the sources without committing.
+//
+// The processors are left to finish the messages in flight, as closing
+// them while they are in use is not safe. They are closed once drained.
+func (t *streamTask) abortTopology(drained chan struct{}) {
+	_ = t.supervisor.Close()
+
+	for _, srcPump := range t.srcPumps {
+		_ = srcPump.Close()
+	}
+
+	go func() {
+		<-drained
+
+		for _, node := range flattenNodeTree(t.sources) {
+			_ = t.pumps[node].Close()
+		}
+
+		t.monitor.Close()
+	}()
+}
+
+// errorHandler returns an error handler for the current topology.
+//
+// Errors raised by a topology that has since been torn down are ignored.
+func (t *streamTask) errorHandler() ErrorFunc {
+	gen := atomic.LoadUint32(&t.gen)
+
+	return func(err error) {
+		if atomic.LoadUint32(&t.gen) != gen {
+			return
+		}
+
+		t.handleError(err)
+	}
+}
+
 func (t *streamTask) handleError(err error) {
-	t.running = false
+	t.lastErr.Store(taskError{err: err})
+
+	t.resetRestarts()
+	if t.canRestart(err) {
+		if atomic.CompareAndSwapInt32(&t.restarting, 0, 1) {
+			go t.restart()
+		}
+
+		return
+	}
+
+	t.fail(err)
+}
+
+// fail stops the task on an error that cannot be recovered from.
+func (t *streamTask) fail(err error) {
+	atomic.StoreInt32(&t.running, 0)
 
 	t.errorFn(err)
+	t.finish(err)
 }
 
 // OnError sets the error handler.
@@ -258,6 +607,64 @@ func (tasks Tasks) OnError(fn ErrorFunc) {
 	})
 }
 
+// Pause stops consuming from the sources of all tasks.
+// An error is returned for tasks that are not a ManagedTask.
+func (tasks Tasks) Pause() error {
+	return tasks.each(func(t Task) error {
+		mt, ok := t.(ManagedTask)
+		if !ok {
+			return errors.New("streams: task cannot be paused")
+		}
+
+		return mt.Pause()
+	})
+}
+
+// Resume resumes consuming from the sources of all tasks.
+// An error is returned for tasks that are not a ManagedTask.
+func (tasks Tasks) Resume() error {
+	return tasks.each(func(t Task) error {
+		mt, ok := t.(ManagedTask)
+		if !ok {
+			return errors.New("streams: task cannot be resumed")
+		}
+
+		return mt.Resume()
+	})
+}
+
+// Wait blocks until all tasks have finished, returning the first error that stopped a task.
+// Tasks that are not a ManagedTask are not waited for.
+func (tasks Tasks) Wait() error {
+	var err error
+	for _, t := range tasks {
+		mt, ok := t.(ManagedTask)
+		if !ok {
+			continue
+		}
+
+		if terr := mt.Wait(); terr != nil && err == nil {
+			err = terr
+		}
+	}
+
+	return err
+}
+
+// Shutdown gracefully stops and closes the streams processors.
+// Tasks that are not a ManagedTask are closed instead.
+// This function operates on the tasks in the reversed order.
+func (tasks Tasks) Shutdown(ctx context.Context) error {
+	return tasks.eachRev(func(t Task) error {
+		mt, ok := t.(ManagedTask)
+		if !ok {
+			return t.Close()
+		}
+
+		return mt.Shutdown(ctx)
+	})
+}
+
 // Close stops and closes the streams processors.
 // This function operates on the tasks in the reversed order.
 func (tasks Tasks) Close() error {
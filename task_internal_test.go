@@ -48,6 +48,7 @@ func TestStreamTask_StartSupervisorStartError(t *testing.T) {
 		topology:        &Topology{sources: map[Source]Node{}},
 		supervisor:      &fakeSupervisor{StartErr: errors.New("start error")},
 		monitorInterval: time.Second,
+		closing:         make(chan struct{}),
 	}
 	defer task.Close()
 
@@ -61,6 +62,7 @@ func TestStreamTask_CloseSupervisorCommitError(t *testing.T) {
 	task := &streamTask{
 		topology:   &Topology{sources: map[Source]Node{}},
 		supervisor: &fakeSupervisor{CommitError: errors.New("commit error")},
+		closing:    make(chan struct{}),
 	}
 
 	err := task.Close()
@@ -73,6 +75,7 @@ func TestStreamTask_CloseSupervisorCloseError(t *testing.T) {
 	task := &streamTask{
 		topology:   &Topology{sources: map[Source]Node{}},
 		supervisor: &fakeSupervisor{CloseErr: errors.New("close error")},
+		closing:    make(chan struct{}),
 	}
 
 	err := task.Close()
@@ -3,6 +3,9 @@ package streams_test
 import (
 	"context"
 	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -55,6 +58,74 @@ func TestStreamTask_ConsumesAsyncMessages(t *testing.T) {
 	p.AssertExpectations(t)
 }
 
+func TestStreamTask_ConsumesAsyncMessagesWithWorkers(t *testing.T) {
+	msgs := make(chan streams.Message)
+	p := newRecordingProcessor()
+
+	b := streams.NewStreamBuilder()
+	b.Source("src", &chanSource{msgs: msgs}).
+		Map("pass-through", streams.MapperFunc(passThroughMapper), streams.WithWorkers(4), streams.WithBufferSize(5)).
+		Process("processor", p)
+
+	tp, _ := b.Build()
+	task := streams.NewTask(tp, streams.WithMode(streams.Async))
+	task.OnError(func(err error) {
+		t.FailNow()
+	})
+
+	err := task.Start(context.Background())
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	var want []interface{}
+	for i := 0; i < 50; i++ {
+		want = append(want, i)
+		msgs <- streams.NewMessage("a", i)
+		msgs <- streams.NewMessage("b", i)
+	}
+
+	_ = task.Close()
+
+	assert.Equal(t, want, p.msgs["a"])
+	assert.Equal(t, want, p.msgs["b"])
+}
+
+func TestStreamTask_DrainsAsyncMapOnClose(t *testing.T) {
+	msgs := make(chan streams.Message)
+	p := newRecordingProcessor()
+
+	b := streams.NewStreamBuilder()
+	b.Source("src", &chanSource{msgs: msgs}).
+		AsyncMapFunc("slow", func(msg streams.Message) (streams.Message, error) {
+			time.Sleep(time.Millisecond)
+			return msg, nil
+		}, 8).
+		Process("processor", p)
+
+	tp, _ := b.Build()
+	task := streams.NewTask(tp, streams.WithMode(streams.Async))
+	task.OnError(func(err error) {
+		t.FailNow()
+	})
+
+	err := task.Start(context.Background())
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	var want []interface{}
+	for i := 0; i < 20; i++ {
+		want = append(want, i)
+		msgs <- streams.NewMessage("a", i)
+	}
+
+	err = task.Close()
+
+	assert.NoError(t, err)
+	assert.Equal(t, want, p.msgs["a"])
+}
+
 func TestStreamTask_ConsumesSyncMessages(t *testing.T) {
 	msgs := make(chan streams.Message)
 	msg := streams.NewMessage("test", "test")
@@ -146,6 +217,167 @@ func TestStreamTask_CannotStartTwice(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestStreamTask_PauseAndResume(t *testing.T) {
+	msgs := make(chan streams.Message)
+	p := newRecordingProcessor()
+
+	b := streams.NewStreamBuilder()
+	b.Source("src", &chanSource{msgs: msgs}).
+		Process("processor", p)
+
+	tp, _ := b.Build()
+	task := streams.NewTask(tp)
+
+	err := task.Start(context.Background())
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	defer task.Close()
+
+	err = task.Pause()
+	assert.NoError(t, err)
+
+	select {
+	case msgs <- streams.NewMessage("test", "test"):
+		assert.Fail(t, "Expected paused task not to consume")
+	case <-time.After(5 * time.Millisecond):
+	}
+
+	err = task.Resume()
+	assert.NoError(t, err)
+
+	select {
+	case msgs <- streams.NewMessage("test", "test"):
+	case <-time.After(time.Second):
+		assert.Fail(t, "Expected resumed task to consume")
+	}
+}
+
+func TestStreamTask_PauseNotRunning(t *testing.T) {
+	task := streams.NewTask(&streams.Topology{})
+
+	assert.Error(t, task.Pause())
+	assert.Error(t, task.Resume())
+}
+
+func TestStreamTask_Shutdown(t *testing.T) {
+	msgs := make(chan streams.Message)
+	p := newRecordingProcessor()
+
+	b := streams.NewStreamBuilder()
+	b.Source("src", &chanSource{msgs: msgs}).
+		MapFunc("slow", func(msg streams.Message) (streams.Message, error) {
+			time.Sleep(time.Millisecond)
+			return msg, nil
+		}).
+		Process("processor", p)
+
+	tp, _ := b.Build()
+	task := streams.NewTask(tp)
+
+	err := task.Start(context.Background())
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	for i := 0; i < 10; i++ {
+		msgs <- streams.NewMessage("test", i)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err = task.Shutdown(ctx)
+
+	assert.NoError(t, err)
+	assert.Len(t, p.msgs["test"], 10)
+}
+
+func TestStreamTask_ShutdownAbortsOnDeadline(t *testing.T) {
+	msgs := make(chan streams.Message)
+	block := make(chan struct{})
+	defer close(block)
+
+	b := streams.NewStreamBuilder()
+	b.Source("src", &chanSource{msgs: msgs}).
+		MapFunc("blocking", func(msg streams.Message) (streams.Message, error) {
+			<-block
+			return msg, nil
+		}).
+		Process("processor", newRecordingProcessor())
+
+	tp, _ := b.Build()
+	task := streams.NewTask(tp)
+
+	err := task.Start(context.Background())
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	msgs <- streams.NewMessage("test", "test")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err = task.Shutdown(ctx)
+
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestStreamTask_CompletesBoundedSources(t *testing.T) {
+	src := &boundedSource{values: []interface{}{1, 2, 3}}
+	p := &markingCommitter{}
+
+	b := streams.NewStreamBuilder()
+	b.Source("src", src).
+		MapFunc("pass-through", passThroughMapper).
+		Process("processor", p)
+
+	tp, _ := b.Build()
+	task := streams.NewTask(tp, streams.WithMode(streams.Async))
+	task.OnError(func(err error) {
+		t.FailNow()
+	})
+
+	err := task.Start(context.Background())
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	select {
+	case <-task.Done():
+	case <-time.After(time.Second):
+		assert.FailNow(t, "Expected task to complete")
+	}
+
+	assert.NoError(t, task.Wait())
+	assert.Equal(t, []interface{}{1, 2, 3}, p.values)
+	assert.Equal(t, 1, p.commits)
+	assert.Equal(t, offsetMetadata(3), src.committed)
+	assert.NoError(t, task.Close())
+}
+
+func TestStreamTask_WaitReturnsError(t *testing.T) {
+	source := new(MockSource)
+	source.On("Consume").Return(streams.Message{}, errors.New("test error"))
+	source.On("Close").Return(nil)
+
+	b := streams.NewStreamBuilder()
+	b.Source("src", source)
+
+	tp, _ := b.Build()
+	task := streams.NewTask(tp)
+	task.OnError(func(err error) {})
+
+	err := task.Start(context.Background())
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	defer task.Close()
+
+	assert.EqualError(t, task.Wait(), "test error")
+}
+
 func TestStreamTask_HandleSourceError(t *testing.T) {
 	gotError := false
 
@@ -171,6 +403,245 @@ func TestStreamTask_HandleSourceError(t *testing.T) {
 	assert.True(t, gotError)
 }
 
+func TestStreamTask_PauseConcurrentlyWithFailure(t *testing.T) {
+	source := new(MockSource)
+	source.On("Consume").Return(streams.Message{}, errors.New("test"))
+	source.On("Close").Return(nil)
+
+	b := streams.NewStreamBuilder()
+	b.Source("src", source).
+		Process("processor", newRecordingProcessor())
+
+	tp, _ := b.Build()
+	task := streams.NewTask(tp)
+
+	err := task.Start(context.Background())
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	paused := make(chan struct{})
+	go func() {
+		defer close(paused)
+
+		for i := 0; i < 100; i++ {
+			_ = task.Pause()
+			_ = task.Resume()
+		}
+	}()
+
+	select {
+	case <-task.Done():
+	case <-time.After(time.Second):
+		assert.FailNow(t, "Expected the task to fail")
+	}
+	<-paused
+
+	assert.Error(t, task.Pause())
+	_ = task.Close()
+}
+
+func TestStreamTask_RestartsOnError(t *testing.T) {
+	failing := new(MockSource)
+	failing.On("Consume").Return(streams.Message{}, errors.New("test"))
+	failing.On("Close").Return(nil)
+	msgs := make(chan streams.Message)
+	factoryCalls := 0
+	p := newRecordingProcessor()
+
+	b := streams.NewStreamBuilder()
+	b.Source("src", failing).
+		Process("processor", p)
+
+	tp, _ := b.Build()
+	task := streams.NewTask(tp, streams.WithRestartPolicy(streams.RestartPolicy{
+		MaxRestarts: 3,
+		MinBackoff:  time.Millisecond,
+		Sources: map[string]streams.SourceFactory{
+			"src": func() (streams.Source, error) {
+				factoryCalls++
+				return &chanSource{msgs: msgs}, nil
+			},
+		},
+	}))
+	task.OnError(func(err error) {
+		t.Error("Expected the task to restart")
+	})
+
+	err := task.Start(context.Background())
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	select {
+	case msgs <- streams.NewMessage("test", "test"):
+	case <-time.After(time.Second):
+		assert.FailNow(t, "Expected the restarted task to consume")
+	}
+
+	_ = task.Close()
+
+	assert.Equal(t, 1, factoryCalls)
+	assert.Equal(t, []interface{}{"test"}, p.msgs["test"])
+	failing.AssertCalled(t, "Close")
+}
+
+func TestStreamTask_RestartBudgetExhausted(t *testing.T) {
+	newFailingSource := func() (streams.Source, error) {
+		src := new(MockSource)
+		src.On("Consume").Return(streams.Message{}, errors.New("test"))
+		src.On("Close").Return(nil)
+
+		return src, nil
+	}
+	first, _ := newFailingSource()
+	factoryCalls := int32(0)
+
+	b := streams.NewStreamBuilder()
+	b.Source("src", first).
+		Process("processor", newRecordingProcessor())
+
+	tp, _ := b.Build()
+	task := streams.NewTask(tp, streams.WithRestartPolicy(streams.RestartPolicy{
+		MaxRestarts: 2,
+		Sources: map[string]streams.SourceFactory{
+			"src": func() (streams.Source, error) {
+				atomic.AddInt32(&factoryCalls, 1)
+				return newFailingSource()
+			},
+		},
+	}))
+	errs := make(chan error, 1)
+	task.OnError(func(err error) {
+		errs <- err
+	})
+
+	err := task.Start(context.Background())
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	select {
+	case err = <-errs:
+	case <-time.After(time.Second):
+		assert.FailNow(t, "Expected the error handler to be called")
+	}
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&factoryCalls))
+}
+
+func TestStreamTask_RestartCancelledDuringBackoff(t *testing.T) {
+	source := new(MockSource)
+	source.On("Consume").Return(streams.Message{}, errors.New("test"))
+	source.On("Close").Return(nil)
+
+	b := streams.NewStreamBuilder()
+	b.Source("src", source).
+		Process("processor", newRecordingProcessor())
+
+	tp, _ := b.Build()
+	task := streams.NewTask(tp, streams.WithRestartPolicy(streams.RestartPolicy{
+		MaxRestarts: 2,
+		MinBackoff:  time.Hour,
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := task.Start(ctx)
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	assert.Eventually(t, func() bool {
+		return task.Status().State == streams.StateRestarting
+	}, time.Second, time.Millisecond)
+	cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- task.Wait()
+	}()
+
+	select {
+	case err = <-done:
+	case <-time.After(time.Second):
+		assert.FailNow(t, "Expected the task to finish")
+	}
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, streams.StateFailed, task.Status().State)
+	_ = task.Close()
+}
+
+func TestStreamTask_ResetsRestartsAfterHealthyRun(t *testing.T) {
+	first := new(MockSource)
+	first.On("Consume").Return(streams.Message{}, errors.New("test"))
+	first.On("Close").Return(nil)
+	factoryCalls := int32(0)
+
+	b := streams.NewStreamBuilder()
+	b.Source("src", first).
+		Process("processor", newRecordingProcessor())
+
+	tp, _ := b.Build()
+	task := streams.NewTask(tp, streams.WithRestartPolicy(streams.RestartPolicy{
+		MaxRestarts: 1,
+		ResetAfter:  5 * time.Millisecond,
+		Sources: map[string]streams.SourceFactory{
+			"src": func() (streams.Source, error) {
+				atomic.AddInt32(&factoryCalls, 1)
+				return &delayedFailingSource{failAt: time.Now().Add(20 * time.Millisecond)}, nil
+			},
+		},
+	}))
+	task.OnError(func(err error) {
+		t.Error("Expected the task to restart")
+	})
+
+	err := task.Start(context.Background())
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&factoryCalls) >= 3
+	}, time.Second, time.Millisecond)
+	_ = task.Close()
+}
+
+func TestStreamTask_DoesNotRestartOnUnrecoverableError(t *testing.T) {
+	source := new(MockSource)
+	source.On("Consume").Return(streams.Message{}, errors.New("test"))
+	source.On("Close").Return(nil)
+
+	b := streams.NewStreamBuilder()
+	b.Source("src", source).
+		Process("processor", newRecordingProcessor())
+
+	tp, _ := b.Build()
+	task := streams.NewTask(tp, streams.WithRestartPolicy(streams.RestartPolicy{
+		MaxRestarts: 2,
+		Recoverable: func(error) bool { return false },
+	}))
+	errs := make(chan error, 1)
+	task.OnError(func(err error) {
+		errs <- err
+	})
+
+	err := task.Start(context.Background())
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	select {
+	case err = <-errs:
+	case <-time.After(time.Second):
+		assert.FailNow(t, "Expected the error handler to be called")
+	}
+
+	assert.Error(t, err)
+}
+
 func TestStreamTask_HandleProcessorError(t *testing.T) {
 	gotError := false
 
@@ -299,6 +770,76 @@ func TestTasks_OnError(t *testing.T) {
 	assert.True(t, t2.onErrorCalled.Before(t3.onErrorCalled))
 }
 
+func TestTasks_PauseAndResume(t *testing.T) {
+	t1, t2 := new(MockTask), new(MockTask)
+	t1.On("Pause").Return(nil)
+	t1.On("Resume").Return(nil)
+	t2.On("Pause").Return(nil)
+	t2.On("Resume").Return(nil)
+
+	tasks := streams.Tasks{t1, t2}
+
+	assert.NoError(t, tasks.Pause())
+	assert.NoError(t, tasks.Resume())
+	t1.AssertExpectations(t)
+	t2.AssertExpectations(t)
+}
+
+func TestTasks_Shutdown(t *testing.T) {
+	ctx := context.Background()
+	t1, t2, t3 := new(MockTask), new(MockTask), new(MockTask)
+	t1.On("Shutdown", ctx).Return(nil)
+	t2.On("Shutdown", ctx).Return(nil)
+	t3.On("Shutdown", ctx).Return(nil)
+
+	tasks := streams.Tasks{t1, t2, t3}
+
+	err := tasks.Shutdown(ctx)
+
+	assert.NoError(t, err)
+	t1.AssertExpectations(t)
+	t2.AssertExpectations(t)
+	t3.AssertExpectations(t)
+	assert.True(t, t1.closeCalled.After(t2.closeCalled))
+	assert.True(t, t2.closeCalled.After(t3.closeCalled))
+}
+
+func TestTasks_ShutdownClosesUnmanagedTasks(t *testing.T) {
+	ctx := context.Background()
+	t1, t2 := new(MockTask), new(MockTask)
+	t1.On("Shutdown", ctx).Return(nil)
+	t2.On("Close").Return(nil)
+
+	tasks := streams.Tasks{t1, struct{ streams.Task }{t2}}
+
+	err := tasks.Shutdown(ctx)
+
+	assert.NoError(t, err)
+	t1.AssertExpectations(t)
+	t2.AssertExpectations(t)
+}
+
+func TestTasks_PauseUnmanagedTask(t *testing.T) {
+	tasks := streams.Tasks{struct{ streams.Task }{new(MockTask)}}
+
+	assert.Error(t, tasks.Pause())
+	assert.Error(t, tasks.Resume())
+}
+
+func TestTasks_Wait(t *testing.T) {
+	t1, t2 := new(MockTask), new(MockTask)
+	t1.On("Wait").Return(errors.New("test error"))
+	t2.On("Wait").Return(nil)
+
+	tasks := streams.Tasks{t1, t2}
+
+	err := tasks.Wait()
+
+	assert.EqualError(t, err, "test error")
+	t1.AssertExpectations(t)
+	t2.AssertExpectations(t)
+}
+
 func TestTasks_Close(t *testing.T) {
 	t1, t2, t3 := new(MockTask), new(MockTask), new(MockTask)
 	t1.On("Close").Return(nil)
@@ -356,3 +897,84 @@ func (s *chanSource) Close() error {
 
 	return nil
 }
+
+type boundedSource struct {
+	mu        sync.Mutex
+	values    []interface{}
+	pos       int
+	committed interface{}
+}
+
+func (s *boundedSource) Consume() (streams.Message, error) {
+	if s.pos >= len(s.values) {
+		return streams.EmptyMessage, io.EOF
+	}
+
+	v := s.values[s.pos]
+	s.pos++
+
+	return streams.NewMessage(nil, v).WithMetadata(s, offsetMetadata(s.pos)), nil
+}
+
+func (s *boundedSource) Commit(v interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.committed = v
+
+	return nil
+}
+
+func (s *boundedSource) Close() error {
+	return nil
+}
+
+type markingCommitter struct {
+	pipe streams.Pipe
+
+	values  []interface{}
+	commits int
+}
+
+func (p *markingCommitter) WithPipe(pipe streams.Pipe) {
+	p.pipe = pipe
+}
+
+func (p *markingCommitter) Process(msg streams.Message) error {
+	p.values = append(p.values, msg.Value)
+
+	return p.pipe.Mark(msg)
+}
+
+func (p *markingCommitter) Commit(ctx context.Context) error {
+	p.commits++
+
+	return nil
+}
+
+func (p *markingCommitter) Close() error {
+	return nil
+}
+
+// delayedFailingSource consumes empty messages until it fails for good.
+type delayedFailingSource struct {
+	failAt time.Time
+}
+
+func (s *delayedFailingSource) Consume() (streams.Message, error) {
+	if time.Now().After(s.failAt) {
+		return streams.Message{}, errors.New("test")
+	}
+
+	time.Sleep(time.Millisecond)
+
+	return streams.EmptyMessage, nil
+}
+
+func (s *delayedFailingSource) Commit(interface{}) error {
+	return nil
+}
+
+func (s *delayedFailingSource) Close() error {
+	return nil
+}
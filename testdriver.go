@@ -0,0 +1,302 @@
+package streams
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// TestDriverOptFunc represents a function that sets up the TopologyTestDriver.
+type TestDriverOptFunc func(d *TopologyTestDriver)
+
+// WithTestCommitInterval defines the interval of virtual time between automatic commits.
+func WithTestCommitInterval(d time.Duration) TestDriverOptFunc {
+	return func(td *TopologyTestDriver) {
+		td.interval = d
+	}
+}
+
+// WithTestMetadataStrategy defines the strategy metadata is merged with on commit.
+func WithTestMetadataStrategy(strategy MetadataStrategy) TestDriverOptFunc {
+	return func(td *TopologyTestDriver) {
+		td.strategy = strategy
+	}
+}
+
+// WithTestStartTime defines the initial virtual time of the driver.
+func WithTestStartTime(t time.Time) TestDriverOptFunc {
+	return func(td *TopologyTestDriver) {
+		td.now = t
+	}
+}
+
+// TopologyTestDriver runs a Topology synchronously, for use in tests.
+//
+// Messages are piped into the topology by source node name, and are processed
+// to completion before PipeInput returns. The messages accepted by every
+// processor node are recorded, and can be read by node name. Commits are
+// triggered by the processors, by Commit, or by advancing the virtual clock
+// past the commit interval. The metadata committed to each source is recorded
+// instead of being committed to the source itself.
+//
+// The virtual clock only drives the timed commits. It is not exposed to the
+// processors, so time based behaviour of a processor, such as a timer or a
+// call to time.Now, still runs on the wall clock and is not made deterministic
+// by AdvanceTime. Such processors should take their clock as a dependency,
+// which the test can drive along with the driver using Now.
+//
+// The sources of the topology are never consumed, committed or closed.
+type TopologyTestDriver struct {
+	interval time.Duration
+	strategy MetadataStrategy
+	now      time.Time
+	next     time.Time
+
+	sources    map[string]*driverSource
+	srcPumps   map[string][]Pump
+	nodes      []Node
+	pumps      map[Node]Pump
+	supervisor *driverSupervisor
+
+	mu      sync.Mutex
+	outputs map[string][]Message
+}
+
+// NewTopologyTestDriver creates a new TopologyTestDriver for the topology.
+func NewTopologyTestDriver(topology *Topology, opts ...TestDriverOptFunc) *TopologyTestDriver {
+	d := &TopologyTestDriver{
+		strategy: Lossless,
+		now:      time.Unix(0, 0).UTC(),
+		sources:  map[string]*driverSource{},
+		srcPumps: map[string][]Pump{},
+		pumps:    map[Node]Pump{},
+		outputs:  map[string][]Message{},
+	}
+
+	for _, optFn := range opts {
+		optFn(d)
+	}
+	d.next = d.now.Add(d.interval)
+
+	store := NewMetastore()
+	d.supervisor = &driverSupervisor{Supervisor: NewSupervisor(store, d.strategy)}
+
+	d.nodes = flattenNodeTree(topology.Sources())
+	reverseNodes(d.nodes)
+	for _, node := range d.nodes {
+		pipe := NewPipe(store, d.supervisor, node.Processor(), d.resolvePumps(node.Children()))
+		node.Processor().WithPipe(pipe)
+
+		d.pumps[node] = &driverPump{
+			Pump:   NewSyncPump(nullMonitor{}, node, pipe.(TimedPipe)),
+			name:   node.Name(),
+			driver: d,
+		}
+	}
+	// Nodes are drained from the roots down, so messages forwarded
+	// while draining a node are drained with its children.
+	reverseNodes(d.nodes)
+
+	d.supervisor.WithPumps(d.pumps)
+
+	for _, node := range topology.Sources() {
+		d.sources[node.Name()] = &driverSource{name: node.Name()}
+		d.srcPumps[node.Name()] = d.resolvePumps(node.Children())
+	}
+
+	return d
+}
+
+func (d *TopologyTestDriver) resolvePumps(nodes []Node) []Pump {
+	var pumps []Pump
+	for _, node := range nodes {
+		pumps = append(pumps, d.pumps[node])
+	}
+	return pumps
+}
+
+// PipeInput pipes the messages into the topology through the named source node.
+//
+// The metadata of the messages is attributed to the source node, and can be
+// inspected with Committed once committed.
+func (d *TopologyTestDriver) PipeInput(source string, msgs ...Message) error {
+	src, ok := d.sources[source]
+	if !ok {
+		return xerrors.Errorf("streams: unknown source %q", source)
+	}
+
+	for _, msg := range msgs {
+		_, meta := msg.Metadata()
+		msg = msg.WithMetadata(src, meta)
+
+		for _, pump := range d.srcPumps[source] {
+			if err := pump.Accept(msg); err != nil {
+				return err
+			}
+		}
+
+		d.drain()
+	}
+
+	return nil
+}
+
+// drain waits for the messages in flight in the processors to be forwarded.
+func (d *TopologyTestDriver) drain() {
+	for _, node := range d.nodes {
+		drain(node.Processor())
+	}
+}
+
+// ReadOutput returns and clears the messages accepted by the named node.
+func (d *TopologyTestDriver) ReadOutput(node string) []Message {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	msgs := d.outputs[node]
+	delete(d.outputs, node)
+
+	return msgs
+}
+
+// Now returns the virtual time of the driver.
+func (d *TopologyTestDriver) Now() time.Time {
+	return d.now
+}
+
+// AdvanceTime advances the virtual time of the driver, performing a commit
+// every time the commit interval elapses.
+//
+// As with a timed task, a commit triggered by a processor since the last
+// interval skips the next timed commit.
+//
+// Only the timed commits follow the virtual time. The processors are not
+// aware of it, and their time based behaviour is unaffected.
+func (d *TopologyTestDriver) AdvanceTime(dur time.Duration) error {
+	d.now = d.now.Add(dur)
+	if d.interval <= 0 {
+		return nil
+	}
+
+	for !d.now.Before(d.next) {
+		d.next = d.next.Add(d.interval)
+
+		if atomic.SwapUint32(&d.supervisor.commits, 0) > 0 {
+			continue
+		}
+
+		if err := d.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Commit performs a global commit sequence.
+func (d *TopologyTestDriver) Commit() error {
+	d.drain()
+
+	return d.supervisor.Supervisor.Commit(nil)
+}
+
+// Committed returns the metadata committed to the named source node, in commit order.
+func (d *TopologyTestDriver) Committed(source string) []Metadata {
+	src, ok := d.sources[source]
+	if !ok {
+		return nil
+	}
+
+	return src.committed
+}
+
+// Close commits any outstanding metadata and closes the processors.
+func (d *TopologyTestDriver) Close() error {
+	if err := d.Commit(); err != nil {
+		return err
+	}
+
+	if err := d.supervisor.Close(); err != nil {
+		return err
+	}
+
+	for _, node := range d.nodes {
+		if err := d.pumps[node].Close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// record records the messages accepted by the named node.
+func (d *TopologyTestDriver) record(node string, msgs ...Message) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.outputs[node] = append(d.outputs[node], msgs...)
+}
+
+// driverSupervisor counts the commits triggered by processors.
+type driverSupervisor struct {
+	Supervisor
+
+	commits uint32
+}
+
+// Commit performs a global commit sequence.
+func (s *driverSupervisor) Commit(caller Processor) error {
+	atomic.AddUint32(&s.commits, 1)
+
+	return s.Supervisor.Commit(caller)
+}
+
+// driverPump records the messages accepted by a node.
+type driverPump struct {
+	Pump
+
+	name   string
+	driver *TopologyTestDriver
+}
+
+// Accept takes a message to be processed in the Pump.
+func (p *driverPump) Accept(msg Message) error {
+	p.driver.record(p.name, msg)
+
+	return p.Pump.Accept(msg)
+}
+
+// AcceptBatch takes a batch of messages to be processed in the Pump.
+func (p *driverPump) AcceptBatch(msgs []Message) error {
+	p.driver.record(p.name, msgs...)
+
+	return acceptBatch(p.Pump, msgs)
+}
+
+// driverSource records the metadata committed to a source node.
+type driverSource struct {
+	name string
+
+	committed []Metadata
+}
+
+// Consume returns no messages, as messages are piped by the driver.
+func (s *driverSource) Consume() (Message, error) {
+	return EmptyMessage, nil
+}
+
+// Commit records the committed metadata.
+func (s *driverSource) Commit(v interface{}) error {
+	if meta, ok := v.(Metadata); ok && meta != nil {
+		s.committed = append(s.committed, meta)
+	}
+
+	return nil
+}
+
+// Close closes the source.
+func (s *driverSource) Close() error {
+	return nil
+}
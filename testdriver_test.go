@@ -0,0 +1,132 @@
+package streams_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDriverTopology(p streams.Processor) *streams.Topology {
+	b := streams.NewStreamBuilder()
+	branches := b.Source("src", &fakeSource{}).
+		AsyncMapFunc("double", func(msg streams.Message) (streams.Message, error) {
+			msg.Value = msg.Value.(int) * 2
+			return msg, nil
+		}, 4).
+		BranchFunc("branch", func(msg streams.Message) (bool, error) {
+			return msg.Value.(int) > 4, nil
+		}, func(msg streams.Message) (bool, error) {
+			return msg.Value.(int) <= 4, nil
+		})
+	branches[0].Process("big", p)
+	branches[1].MapFunc("small", passThroughMapper)
+
+	tp, _ := b.Build()
+
+	return tp
+}
+
+func TestTopologyTestDriver_PipeInput(t *testing.T) {
+	p := &markingCommitter{}
+	d := streams.NewTopologyTestDriver(newTestDriverTopology(p))
+
+	err := d.PipeInput("src",
+		streams.NewMessage(nil, 1).WithMetadata(nil, offsetMetadata(1)),
+		streams.NewMessage(nil, 2).WithMetadata(nil, offsetMetadata(2)),
+		streams.NewMessage(nil, 3).WithMetadata(nil, offsetMetadata(3)),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{6}, p.values)
+	assert.Equal(t, []interface{}{6}, values(d.ReadOutput("big")))
+	assert.Equal(t, []interface{}{2, 4}, values(d.ReadOutput("small")))
+	assert.Len(t, d.ReadOutput("big"), 0)
+}
+
+func TestTopologyTestDriver_PipeInputUnknownSource(t *testing.T) {
+	d := streams.NewTopologyTestDriver(newTestDriverTopology(&markingCommitter{}))
+
+	err := d.PipeInput("nope", streams.NewMessage(nil, 1))
+
+	assert.Error(t, err)
+}
+
+func TestTopologyTestDriver_PipeInputError(t *testing.T) {
+	b := streams.NewStreamBuilder()
+	b.Source("src", &fakeSource{}).
+		MapFunc("map", func(msg streams.Message) (streams.Message, error) {
+			return msg, errors.New("test error")
+		})
+	tp, _ := b.Build()
+	d := streams.NewTopologyTestDriver(tp)
+
+	err := d.PipeInput("src", streams.NewMessage(nil, 1))
+
+	assert.Error(t, err)
+}
+
+func TestTopologyTestDriver_AdvanceTime(t *testing.T) {
+	p := &markingCommitter{}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := streams.NewTopologyTestDriver(newTestDriverTopology(p),
+		streams.WithTestCommitInterval(time.Second),
+		streams.WithTestStartTime(start),
+	)
+
+	_ = d.PipeInput("src", streams.NewMessage(nil, 3).WithMetadata(nil, offsetMetadata(1)))
+
+	err := d.AdvanceTime(500 * time.Millisecond)
+
+	assert.NoError(t, err)
+	assert.Equal(t, start.Add(500*time.Millisecond), d.Now())
+	assert.Len(t, d.Committed("src"), 0)
+
+	err = d.AdvanceTime(500 * time.Millisecond)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, p.commits)
+	assert.Equal(t, []streams.Metadata{offsetMetadata(1)}, d.Committed("src"))
+
+	_ = d.PipeInput("src", streams.NewMessage(nil, 4).WithMetadata(nil, offsetMetadata(2)))
+
+	err = d.AdvanceTime(3 * time.Second)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, p.commits)
+	assert.Equal(t, []streams.Metadata{offsetMetadata(1), offsetMetadata(2)}, d.Committed("src"))
+}
+
+func TestTopologyTestDriver_AdvanceTimeWithoutInterval(t *testing.T) {
+	p := &markingCommitter{}
+	d := streams.NewTopologyTestDriver(newTestDriverTopology(p))
+
+	err := d.AdvanceTime(time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, p.commits)
+}
+
+func TestTopologyTestDriver_Close(t *testing.T) {
+	p := &markingCommitter{}
+	d := streams.NewTopologyTestDriver(newTestDriverTopology(p))
+	_ = d.PipeInput("src", streams.NewMessage(nil, 5).WithMetadata(nil, offsetMetadata(7)))
+
+	err := d.Close()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, p.commits)
+	assert.Equal(t, []streams.Metadata{offsetMetadata(7)}, d.Committed("src"))
+	assert.Nil(t, d.Committed("nope"))
+}
+
+func values(msgs []streams.Message) []interface{} {
+	var vals []interface{}
+	for _, msg := range msgs {
+		vals = append(vals, msg.Value)
+	}
+
+	return vals
+}
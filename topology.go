@@ -55,15 +55,19 @@ var _ = (Node)(&ProcessorNode{})
 type ProcessorNode struct {
 	name      string
 	processor Processor
+	pumpOpts  []PumpOptFunc
 
 	children []Node
 }
 
 // NewProcessorNode creates a new ProcessorNode.
-func NewProcessorNode(name string, p Processor) *ProcessorNode {
+//
+// The pump options are applied to the asynchronous pump of the node.
+func NewProcessorNode(name string, p Processor, opts ...PumpOptFunc) *ProcessorNode {
 	return &ProcessorNode{
 		name:      name,
 		processor: p,
+		pumpOpts:  opts,
 	}
 }
 
@@ -87,6 +91,11 @@ func (n *ProcessorNode) Processor() Processor {
 	return n.processor
 }
 
+// PumpOpts gets the nodes pump options.
+func (n *ProcessorNode) PumpOpts() []PumpOptFunc {
+	return n.pumpOpts
+}
+
 // Topology represents the streams topology.
 type Topology struct {
 	sources    map[Source]Node
@@ -105,17 +114,21 @@ func (t Topology) Processors() []Node {
 
 // TopologyBuilder represents a topology builder.
 type TopologyBuilder struct {
-	inspections []inspection
+	inspections []Inspection
 	sources     map[Source]Node
 	processors  []Node
 }
 
 // NewTopologyBuilder creates a new TopologyBuilder.
 func NewTopologyBuilder() *TopologyBuilder {
-	inspections := []inspection{
+	inspections := []Inspection{
+		noCycles,
+		uniqueNames,
 		sourcesConnected,
 		committersConnected,
 		committerIsLeafNode,
+		branchesConnected,
+		processorsReachable,
 	}
 
 	return &TopologyBuilder{
@@ -135,8 +148,8 @@ func (tb *TopologyBuilder) AddSource(name string, source Source) Node {
 }
 
 // AddProcessor adds a Processor to the builder, returning the created Node.
-func (tb *TopologyBuilder) AddProcessor(name string, processor Processor, parents []Node) Node {
-	n := NewProcessorNode(name, processor)
+func (tb *TopologyBuilder) AddProcessor(name string, processor Processor, parents []Node, opts ...PumpOptFunc) Node {
+	n := NewProcessorNode(name, processor, opts...)
 	for _, parent := range parents {
 		parent.AddChild(n)
 	}
@@ -146,6 +159,11 @@ func (tb *TopologyBuilder) AddProcessor(name string, processor Processor, parent
 	return n
 }
 
+// AddInspection adds an Inspection that is performed on the topology when it is built.
+func (tb *TopologyBuilder) AddInspection(fn Inspection) {
+	tb.inspections = append(tb.inspections, fn)
+}
+
 // Build creates an immutable Topology.
 func (tb *TopologyBuilder) Build() (*Topology, []error) {
 	var errs []error
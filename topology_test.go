@@ -1,6 +1,7 @@
 package streams_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/rafalmnich/streams/v6"
@@ -49,6 +50,13 @@ func TestNewProcessorNode(t *testing.T) {
 	assert.Equal(t, "test", n.Name())
 }
 
+func TestProcessorNode_PumpOpts(t *testing.T) {
+	p := new(MockProcessor)
+	n := streams.NewProcessorNode("test", p, streams.WithWorkers(2), streams.WithBufferSize(10))
+
+	assert.Len(t, n.PumpOpts(), 2)
+}
+
 func TestProcessorNode_AddChild(t *testing.T) {
 	child := &streams.ProcessorNode{}
 	n := streams.ProcessorNode{}
@@ -95,8 +103,8 @@ func TestTopologyBuilder_AddSource(t *testing.T) {
 
 func TestTopologyBuilder_AddProcessor(t *testing.T) {
 	p := new(MockProcessor)
-	pn := &streams.ProcessorNode{}
 	tb := streams.NewTopologyBuilder()
+	pn := tb.AddSource("src", new(MockSource))
 
 	n := tb.AddProcessor("test", p, []streams.Node{pn})
 	to, errs := tb.Build()
@@ -120,7 +128,18 @@ func TestTopologyBuilder_BuildChecksInspections(t *testing.T) {
 
 	_, errs := tb.Build()
 
-	assert.Len(t, errs, 3)
+	assert.Len(t, errs, 5)
+}
+
+func TestTopologyBuilder_AddInspection(t *testing.T) {
+	tb := streams.NewTopologyBuilder()
+	tb.AddInspection(func(srcs map[streams.Source]streams.Node, procs []streams.Node) error {
+		return errors.New("test error")
+	})
+
+	_, errs := tb.Build()
+
+	assert.Equal(t, []error{errors.New("test error")}, errs)
 }
 
 func TestTopology_Sources(t *testing.T) {
@@ -0,0 +1,83 @@
+package streams
+
+import (
+	"context"
+)
+
+// Tracer represents a tracer of the processing of Messages.
+type Tracer interface {
+	// Start starts a span of the processing of the Message in the named node,
+	// as a child of the span in the given context. The returned context carries
+	// the span, and becomes the context of the Message while it is processed.
+	Start(ctx context.Context, node string, msg Message) (context.Context, Span)
+}
+
+// Span represents a span of the processing of a Message.
+type Span interface {
+	// End ends the span, recording the error if the processing failed.
+	End(err error)
+}
+
+// WithTracer sets the tracer of the processing of Messages in the task pumps.
+func WithTracer(tracer Tracer) TaskOptFunc {
+	return func(t *streamTask) {
+		t.tracer = tracer
+	}
+}
+
+// WithPumpTracer sets the tracer of the processing of Messages in the pump.
+func WithPumpTracer(tracer Tracer) PumpOptFunc {
+	return func(o *pumpOpts) {
+		o.tracer = tracer
+	}
+}
+
+// nopSpan is a span that does nothing.
+type nopSpan struct{}
+
+// End ends the span.
+func (nopSpan) End(error) {}
+
+// spans represents the spans of a batch of Messages.
+type spans []Span
+
+// End ends the spans.
+func (s spans) End(err error) {
+	for _, span := range s {
+		span.End(err)
+	}
+}
+
+// startSpan starts a span of the processing of the Message, setting the span on the Message context.
+func startSpan(tracer Tracer, node string, msg Message) (Message, Span) {
+	if tracer == nil {
+		return msg, nopSpan{}
+	}
+
+	ctx := msg.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx, span := tracer.Start(ctx, node, msg)
+	msg.Ctx = ctx
+
+	return msg, span
+}
+
+// startBatchSpans starts a span of the processing of each Message in the batch.
+//
+// The batch is copied when tracing, as it may be shared with other pumps.
+func startBatchSpans(tracer Tracer, node string, msgs []Message) ([]Message, Span) {
+	if tracer == nil {
+		return msgs, nopSpan{}
+	}
+
+	traced := make([]Message, len(msgs))
+	s := make(spans, len(msgs))
+	for i, msg := range msgs {
+		traced[i], s[i] = startSpan(tracer, node, msg)
+	}
+
+	return traced, s
+}
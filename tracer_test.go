@@ -0,0 +1,163 @@
+package streams_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncPump_AcceptTraced(t *testing.T) {
+	tracer := &fakeTracer{}
+	processor := &ctxProcessor{}
+	node := streams.NewProcessorNode("test", processor)
+	pipe := new(MockTimedPipe)
+	pipe.On("Reset")
+	pipe.On("Duration").Return(time.Duration(0))
+	p := streams.NewSyncPump(&fakeMonitor{}, node, pipe, streams.WithPumpTracer(tracer))
+	parent := context.WithValue(context.Background(), ctxKey("parent"), "1")
+
+	err := p.Accept(streams.NewMessageWithContext(parent, "test", "test"))
+
+	assert.NoError(t, err)
+	spans := tracer.ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "test", spans[0].node)
+		assert.Equal(t, parent, spans[0].parent)
+		assert.NoError(t, spans[0].err)
+		assert.Equal(t, []interface{}{spans[0]}, processor.spans())
+	}
+}
+
+func TestSyncPump_AcceptTracedError(t *testing.T) {
+	tracer := &fakeTracer{}
+	processor := &ctxProcessor{err: errors.New("test")}
+	node := streams.NewProcessorNode("test", processor)
+	pipe := new(MockTimedPipe)
+	pipe.On("Reset")
+	pipe.On("Duration").Return(time.Duration(0))
+	p := streams.NewSyncPump(&fakeMonitor{}, node, pipe, streams.WithPumpTracer(tracer))
+
+	err := p.Accept(streams.NewMessage("test", "test"))
+
+	assert.Error(t, err)
+	spans := tracer.ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, context.Background(), spans[0].parent)
+		assert.Equal(t, err, spans[0].err)
+	}
+}
+
+func TestSyncPump_AcceptBatchTraced(t *testing.T) {
+	tracer := &fakeTracer{}
+	processor := &ctxProcessor{}
+	node := streams.NewProcessorNode("test", processor)
+	pipe := new(MockTimedPipe)
+	pipe.On("Reset")
+	pipe.On("Duration").Return(time.Duration(0))
+	p := streams.NewSyncPump(&fakeMonitor{}, node, pipe, streams.WithPumpTracer(tracer))
+	msgs := []streams.Message{streams.NewMessage("test", 1), streams.NewMessage("test", 2)}
+
+	err := p.(streams.BatchPump).AcceptBatch(msgs)
+
+	assert.NoError(t, err)
+	assert.Len(t, tracer.ended(), 2)
+	assert.Len(t, processor.spans(), 2)
+	assert.Nil(t, msgs[0].Ctx.Value(spanKey{}))
+}
+
+func TestAsyncPump_AcceptTraced(t *testing.T) {
+	tracer := &fakeTracer{}
+	processor := &ctxProcessor{}
+	node := streams.NewProcessorNode("test", processor)
+	pipe := new(MockTimedPipe)
+	pipe.On("Reset")
+	pipe.On("Duration").Return(time.Duration(0))
+	p := streams.NewAsyncPump(&fakeMonitor{}, node, pipe, func(error) {}, streams.WithPumpTracer(tracer))
+	defer p.Close()
+	parent := context.WithValue(context.Background(), ctxKey("parent"), "1")
+
+	err := p.Accept(streams.NewMessageWithContext(parent, "test", "test"))
+
+	time.Sleep(3 * time.Millisecond)
+
+	assert.NoError(t, err)
+	spans := tracer.ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "test", spans[0].node)
+		assert.Equal(t, parent, spans[0].parent)
+		assert.Equal(t, []interface{}{spans[0]}, processor.spans())
+	}
+}
+
+type spanKey struct{}
+
+type fakeSpan struct {
+	node   string
+	parent context.Context
+	err    error
+}
+
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, node string, msg streams.Message) (context.Context, streams.Span) {
+	span := &fakeSpan{node: node, parent: ctx}
+
+	return context.WithValue(ctx, spanKey{}, span), &fakeTracerSpan{tracer: t, span: span}
+}
+
+func (t *fakeTracer) ended() []*fakeSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.spans
+}
+
+type fakeTracerSpan struct {
+	tracer *fakeTracer
+	span   *fakeSpan
+}
+
+func (s *fakeTracerSpan) End(err error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	s.span.err = err
+	s.tracer.spans = append(s.tracer.spans, s.span)
+}
+
+type ctxProcessor struct {
+	err error
+
+	mu  sync.Mutex
+	got []interface{}
+}
+
+func (*ctxProcessor) WithPipe(streams.Pipe) {}
+
+func (p *ctxProcessor) Process(msg streams.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.got = append(p.got, msg.Ctx.Value(spanKey{}))
+
+	return p.err
+}
+
+func (p *ctxProcessor) spans() []interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.got
+}
+
+func (*ctxProcessor) Close() error {
+	return nil
+}
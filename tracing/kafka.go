@@ -0,0 +1,152 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/Shopify/sarama"
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/kafka"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Span attribute keys of Messages consumed from a Kafka Source.
+const (
+	KafkaTopicKey     = attribute.Key("messaging.destination")
+	KafkaPartitionKey = attribute.Key("messaging.kafka.partition")
+	KafkaOffsetKey    = attribute.Key("messaging.kafka.offset")
+)
+
+// kafkaAttributes returns the topic, partition and offset of a Message
+// consumed from a Kafka Source.
+//
+// Messages that were not consumed from a Kafka Source have no attributes.
+func kafkaAttributes(msg streams.Message) []attribute.KeyValue {
+	_, v := msg.Metadata()
+
+	meta, ok := v.(kafka.Metadata)
+	if !ok || len(meta) != 1 {
+		return nil
+	}
+
+	return []attribute.KeyValue{
+		KafkaTopicKey.String(meta[0].Topic),
+		KafkaPartitionKey.Int64(int64(meta[0].Partition)),
+		KafkaOffsetKey.Int64(meta[0].Offset),
+	}
+}
+
+// KafkaPropagatorOptFunc represents a function that sets up the KafkaPropagator.
+type KafkaPropagatorOptFunc func(p *KafkaPropagator)
+
+// WithTextMapPropagator sets the propagator of the trace context.
+// The global propagator is used by default.
+func WithTextMapPropagator(propagator propagation.TextMapPropagator) KafkaPropagatorOptFunc {
+	return func(p *KafkaPropagator) {
+		p.propagator = propagator
+	}
+}
+
+var _ = (kafka.Propagator)(&KafkaPropagator{})
+
+// KafkaPropagator propagates the trace context through the headers of Kafka records.
+//
+// It is set as the Propagator of the kafka SourceConfig and SinkConfig.
+type KafkaPropagator struct {
+	propagator propagation.TextMapPropagator
+}
+
+// NewKafkaPropagator creates a new KafkaPropagator.
+func NewKafkaPropagator(opts ...KafkaPropagatorOptFunc) *KafkaPropagator {
+	p := &KafkaPropagator{
+		propagator: otel.GetTextMapPropagator(),
+	}
+
+	for _, optFn := range opts {
+		optFn(p)
+	}
+
+	return p
+}
+
+// Extract returns a copy of the context with the trace context read from the headers of a consumed record.
+func (p *KafkaPropagator) Extract(ctx context.Context, headers []*sarama.RecordHeader) context.Context {
+	return p.propagator.Extract(ctx, consumerHeaders(headers))
+}
+
+// Inject writes the trace context of the context to the headers of a produced record.
+func (p *KafkaPropagator) Inject(ctx context.Context, msg *sarama.ProducerMessage) {
+	p.propagator.Inject(ctx, &producerHeaders{msg: msg})
+}
+
+var _ = (propagation.TextMapCarrier)(consumerHeaders{})
+
+// consumerHeaders adapts the headers of a consumed record to a carrier.
+type consumerHeaders []*sarama.RecordHeader
+
+// Get returns the value associated with the passed key.
+func (h consumerHeaders) Get(key string) string {
+	for _, header := range h {
+		if header != nil && string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+
+	return ""
+}
+
+// Set is a no-op, as consumed headers are read-only.
+func (h consumerHeaders) Set(string, string) {}
+
+// Keys lists the keys stored in this carrier.
+func (h consumerHeaders) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for _, header := range h {
+		if header != nil {
+			keys = append(keys, string(header.Key))
+		}
+	}
+
+	return keys
+}
+
+var _ = (propagation.TextMapCarrier)(&producerHeaders{})
+
+// producerHeaders adapts the headers of a produced record to a carrier.
+type producerHeaders struct {
+	msg *sarama.ProducerMessage
+}
+
+// Get returns the value associated with the passed key.
+func (h *producerHeaders) Get(key string) string {
+	for _, header := range h.msg.Headers {
+		if string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+
+	return ""
+}
+
+// Set stores the key-value pair, replacing an existing header.
+func (h *producerHeaders) Set(key, value string) {
+	for i, header := range h.msg.Headers {
+		if string(header.Key) == key {
+			h.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+
+	h.msg.Headers = append(h.msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+// Keys lists the keys stored in this carrier.
+func (h *producerHeaders) Keys() []string {
+	keys := make([]string, 0, len(h.msg.Headers))
+	for _, header := range h.msg.Headers {
+		keys = append(keys, string(header.Key))
+	}
+
+	return keys
+}
@@ -0,0 +1,91 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/kafka"
+	"github.com/rafalmnich/streams/v6/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/oteltest"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer_StartAddsKafkaAttributes(t *testing.T) {
+	sr := new(oteltest.SpanRecorder)
+	tracer := tracing.NewTracer(tracing.WithTracerProvider(oteltest.NewTracerProvider(oteltest.WithSpanRecorder(sr))))
+	meta := kafka.Metadata{{Topic: "foo", Partition: 2, Offset: 10}}
+	msg := streams.NewMessage(nil, "bar").WithMetadata(nil, meta)
+
+	_, span := tracer.Start(msg.Ctx, "node", msg)
+	span.End(nil)
+
+	spans := sr.Completed()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, map[attribute.Key]attribute.Value{
+			tracing.NodeKey:           attribute.StringValue("node"),
+			tracing.KafkaTopicKey:     attribute.StringValue("foo"),
+			tracing.KafkaPartitionKey: attribute.Int64Value(2),
+			tracing.KafkaOffsetKey:    attribute.Int64Value(10),
+		}, spans[0].Attributes())
+	}
+}
+
+func TestTracer_StartWithoutKafkaMetadata(t *testing.T) {
+	sr := new(oteltest.SpanRecorder)
+	tracer := tracing.NewTracer(tracing.WithTracerProvider(oteltest.NewTracerProvider(oteltest.WithSpanRecorder(sr))))
+	msg := streams.NewMessage(nil, "bar")
+
+	_, span := tracer.Start(msg.Ctx, "node", msg)
+	span.End(nil)
+
+	spans := sr.Completed()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, map[attribute.Key]attribute.Value{
+			tracing.NodeKey: attribute.StringValue("node"),
+		}, spans[0].Attributes())
+	}
+}
+
+func TestKafkaPropagator_Extract(t *testing.T) {
+	p := tracing.NewKafkaPropagator(tracing.WithTextMapPropagator(propagation.TraceContext{}))
+	headers := []*sarama.RecordHeader{
+		nil,
+		{Key: []byte("traceparent"), Value: []byte("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")},
+	}
+
+	ctx := p.Extract(context.Background(), headers)
+
+	sc := trace.SpanContextFromContext(ctx)
+	assert.True(t, sc.IsRemote())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", sc.SpanID().String())
+}
+
+func TestKafkaPropagator_Inject(t *testing.T) {
+	p := tracing.NewKafkaPropagator(tracing.WithTextMapPropagator(propagation.TraceContext{}))
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+	msg := &sarama.ProducerMessage{
+		Headers: []sarama.RecordHeader{{Key: []byte("traceparent"), Value: []byte("stale")}},
+	}
+
+	p.Inject(ctx, msg)
+
+	var traceparent string
+	for _, h := range msg.Headers {
+		if string(h.Key) == "traceparent" {
+			traceparent = string(h.Value)
+		}
+	}
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", traceparent)
+}
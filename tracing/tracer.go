@@ -0,0 +1,98 @@
+// Package tracing implements streams tracing with OpenTelemetry.
+package tracing
+
+import (
+	"context"
+
+	"github.com/rafalmnich/streams/v6"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the tracer created by the Tracer.
+const InstrumentationName = "github.com/rafalmnich/streams"
+
+// NodeKey is the attribute key of the node name.
+const NodeKey = attribute.Key("streams.node")
+
+// AttributesFunc returns the span attributes of a Message.
+type AttributesFunc func(streams.Message) []attribute.KeyValue
+
+// TracerOptFunc represents a function that sets up the Tracer.
+type TracerOptFunc func(t *Tracer)
+
+// WithTracerProvider sets the tracer provider. The global tracer provider is used by default.
+func WithTracerProvider(provider trace.TracerProvider) TracerOptFunc {
+	return func(t *Tracer) {
+		t.provider = provider
+	}
+}
+
+// WithAttributes adds a function returning the span attributes of a Message.
+func WithAttributes(fn AttributesFunc) TracerOptFunc {
+	return func(t *Tracer) {
+		t.attrs = append(t.attrs, fn)
+	}
+}
+
+var _ = (streams.Tracer)(&Tracer{})
+
+// Tracer represents an OpenTelemetry streams tracer.
+//
+// A span is started for every processing of a Message in a node, named after
+// the node, as a child of the span in the Message context. The topic, partition
+// and offset of Messages consumed from a Kafka Source are added as attributes.
+type Tracer struct {
+	provider trace.TracerProvider
+	attrs    []AttributesFunc
+
+	tracer trace.Tracer
+}
+
+// NewTracer creates a new Tracer.
+func NewTracer(opts ...TracerOptFunc) *Tracer {
+	t := &Tracer{
+		provider: otel.GetTracerProvider(),
+	}
+
+	for _, optFn := range opts {
+		optFn(t)
+	}
+
+	t.tracer = t.provider.Tracer(InstrumentationName)
+
+	return t
+}
+
+// Start starts a span of the processing of the Message in the named node.
+func (t *Tracer) Start(ctx context.Context, node string, msg streams.Message) (context.Context, streams.Span) {
+	attrs := []attribute.KeyValue{NodeKey.String(node)}
+	attrs = append(attrs, kafkaAttributes(msg)...)
+	for _, fn := range t.attrs {
+		attrs = append(attrs, fn(msg)...)
+	}
+
+	ctx, span := t.tracer.Start(ctx, node,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attrs...),
+	)
+
+	return ctx, &otelSpan{span: span}
+}
+
+// otelSpan represents an OpenTelemetry span.
+type otelSpan struct {
+	span trace.Span
+}
+
+// End ends the span, recording the error if the processing failed.
+func (s *otelSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+
+	s.span.End()
+}
@@ -0,0 +1,58 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rafalmnich/streams/v6"
+	"github.com/rafalmnich/streams/v6/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/oteltest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer_Start(t *testing.T) {
+	sr := new(oteltest.SpanRecorder)
+	provider := oteltest.NewTracerProvider(oteltest.WithSpanRecorder(sr))
+	tracer := tracing.NewTracer(
+		tracing.WithTracerProvider(provider),
+		tracing.WithAttributes(func(msg streams.Message) []attribute.KeyValue {
+			return []attribute.KeyValue{attribute.String("key", msg.Key.(string))}
+		}),
+	)
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	msg := streams.NewMessageWithContext(ctx, "foo", "bar")
+
+	ctx, span := tracer.Start(msg.Ctx, "node", msg)
+	span.End(nil)
+
+	spans := sr.Completed()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "node", spans[0].Name())
+		assert.Equal(t, trace.SpanKindInternal, spans[0].SpanKind())
+		assert.Equal(t, parent.SpanContext().SpanID(), spans[0].ParentSpanID())
+		assert.Equal(t, spans[0].SpanContext(), trace.SpanContextFromContext(ctx))
+		assert.Equal(t, attribute.StringValue("node"), spans[0].Attributes()[tracing.NodeKey])
+		assert.Equal(t, attribute.StringValue("foo"), spans[0].Attributes()["key"])
+		assert.Equal(t, codes.Unset, spans[0].StatusCode())
+	}
+}
+
+func TestTracer_StartRecordsError(t *testing.T) {
+	sr := new(oteltest.SpanRecorder)
+	tracer := tracing.NewTracer(tracing.WithTracerProvider(oteltest.NewTracerProvider(oteltest.WithSpanRecorder(sr))))
+	msg := streams.NewMessage("foo", "bar")
+
+	_, span := tracer.Start(msg.Ctx, "node", msg)
+	span.End(errors.New("test"))
+
+	spans := sr.Completed()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, codes.Error, spans[0].StatusCode())
+		assert.Equal(t, "test", spans[0].StatusMessage())
+		assert.Len(t, spans[0].Events(), 1)
+	}
+}